@@ -8,7 +8,9 @@ import (
 )
 
 func main() {
-	if err := cli.RootCmd.Execute(); err != nil {
+	err := cli.RootCmd.Execute()
+	cli.PrintQuotaReport()
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
@@ -2,25 +2,51 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
+	"golang.org/x/oauth2/jwt"
+	"google.golang.org/api/drive/v3"
 	"google.golang.org/api/option"
 	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/helpers"
 )
 
 const (
-	CallbackServerPort = ":8080"
-	CredentialsDir     = ".credentials"
-	CredentialsFile    = "google_credentials.json"
-	StateDirMode       = 0700
-	TokenFile          = "token_gdrive.json"
-	TokenFileMode      = 0600
+	ApplicationCredentialsEnv = "GOOGLE_APPLICATION_CREDENTIALS"
+	CallbackReadTimeout       = 10 * time.Second
+	CallbackServerAddr        = "127.0.0.1:0"
+	ConfigDirName             = "spreadsheet-manager"
+	ConfigFile                = "config.json"
+	CredentialsEnv            = "SPREADSHEET_MANAGER_CREDENTIALS"
+	CredentialsFile           = "credentials.json"
+	ImpersonateEnv            = "SPREADSHEET_MANAGER_IMPERSONATE"
+	DefaultProfile            = "default"
+	LegacyCredentialsDir      = ".credentials"
+	LegacyCredentialsFile     = "google_credentials.json"
+	LegacyTokenFile           = "token_gdrive.json"
+	ProfileEnv                = "SPREADSHEET_MANAGER_PROFILE"
+	ProfilesDir               = "profiles"
+	RevokeURL                 = "https://oauth2.googleapis.com/revoke"
+	ServiceAccountEnv         = "SPREADSHEET_MANAGER_SERVICE_ACCOUNT"
+	StateBytes                = 32
+	StateDirMode              = 0700
+	TokenEnv                  = "SPREADSHEET_MANAGER_TOKEN"
+	TokenFile                 = "token.json"
+	TokenFileMode             = 0600
+	TokenInfoURL              = "https://oauth2.googleapis.com/tokeninfo"
 )
 
 var DefaultScopes = []string{
@@ -28,28 +54,110 @@ var DefaultScopes = []string{
 	sheets.DriveScope,
 }
 
-// GetClient retrieves an OAuth2 HTTP client using stored credentials
+// DeviceScopes are requested by the device flow, for which Google only allows drive.file among
+// the Drive and Sheets scopes: such tokens reach the files this tool created or opened
+var DeviceScopes = []string{
+	drive.DriveFileScope,
+}
+
+// Values of the --service-account, --impersonate, --credentials, --token and --profile flags
+var (
+	ServiceAccountFile  string
+	Impersonate         string
+	CredentialsFilePath string
+	TokenFilePath       string
+	Profile             string
+)
+
+// shared holds the client and services of this invocation. They are built on first use and
+// then handed to every caller, goroutines included, so credentials and tokens are read once
+// and connections are reused. Failures are not kept, the next call tries again
+var shared struct {
+	sync.Mutex
+	client *http.Client
+	sheets *sheets.Service
+	drive  *drive.Service
+}
+
+// GetClient retrieves an HTTP client authenticated with a service account key when one is
+// configured, or with the stored OAuth2 user token otherwise. Rate-limited and failed
+// requests are retried (see retryTransport), and every attempt goes through the rate limiter
+// and quota accounting (see meteredTransport). The client is shared for the invocation; the
+// context of the first call is the one used to refresh tokens
 func GetClient(ctx context.Context) (*http.Client, error) {
-	credPath := filepath.Join(getCredentialsPath(), CredentialsFile)
-	tokenPath := filepath.Join(getCredentialsPath(), TokenFile)
+	shared.Lock()
+	defer shared.Unlock()
+	return sharedClient(ctx)
+}
 
-	credentials, err := os.ReadFile(credPath)
+// sharedClient returns the shared client, building it if needed. shared must be locked
+func sharedClient(ctx context.Context) (*http.Client, error) {
+	if shared.client != nil {
+		return shared.client, nil
+	}
+
+	client, err := authenticatedClient(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read credentials file %s: %w\nSee README.md for setup instructions", credPath, err)
+		return nil, err
+	}
+	client.Transport = newRetryTransport(newMeteredTransport(client.Transport))
+	shared.client = client
+	helpers.SetCacheIdentity(cacheIdentity())
+	return client, nil
+}
+
+// cacheIdentity names the account the client acts as, to keep cached metadata apart per
+// account: the service account key and impersonated user, or the token store of the profile
+func cacheIdentity() string {
+	if keyPath := serviceAccountKeyPath(); keyPath != "" {
+		if abs, err := filepath.Abs(keyPath); err == nil {
+			keyPath = abs
+		}
+		return "service-account:" + keyPath + ":" + impersonatedUser()
+	}
+	return "user:" + activeTokenStore().String()
+}
+
+// SetClient replaces the shared client, and drops the services built on the previous one.
+// nil makes the next call authenticate again (after a login or logout), and a client of
+// your own sends every call through it, e.g. to a stub server in tests
+func SetClient(client *http.Client) {
+	shared.Lock()
+	defer shared.Unlock()
+	shared.client = client
+	shared.sheets = nil
+	shared.drive = nil
+	// A client of our own has no known identity, so cached metadata stays in memory
+	if client != nil {
+		helpers.SetCacheIdentity("")
+	}
+}
+
+func authenticatedClient(ctx context.Context) (*http.Client, error) {
+	if keyPath := serviceAccountKeyPath(); keyPath != "" {
+		return serviceAccountClient(ctx, keyPath)
+	}
+	if impersonatedUser() != "" {
+		return nil, fmt.Errorf("--impersonate requires a service account key (--service-account)")
 	}
+	if err := ValidateProfile(ActiveProfile()); err != nil {
+		return nil, err
+	}
+
+	store := activeTokenStore()
 
-	config, err := google.ConfigFromJSON(credentials, DefaultScopes...)
+	config, err := oauthConfig(DefaultScopes...)
 	if err != nil {
-		return nil, fmt.Errorf("unable to parse credentials: %w", err)
+		return nil, err
 	}
 
-	token, err := loadToken(tokenPath)
+	token, err := store.load()
 	if err != nil {
 		token, err = requestTokenFromWeb(ctx, config)
 		if err != nil {
 			return nil, err
 		}
-		if err := saveToken(tokenPath, token); err != nil {
+		if err := store.save(token); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: unable to save token: %v\n", err)
 		}
 	}
@@ -57,27 +165,251 @@ func GetClient(ctx context.Context) (*http.Client, error) {
 	return config.Client(ctx, token), nil
 }
 
-// GetSheetsService creates an authenticated Google Sheets service
+// Login runs an interactive OAuth flow (loopback browser flow, or the device flow for machines
+// without a browser) and stores the new token, replacing any existing one
+func Login(ctx context.Context, device bool) (string, error) {
+	if err := ValidateProfile(ActiveProfile()); err != nil {
+		return "", err
+	}
+
+	scopes := DefaultScopes
+	if device {
+		scopes = DeviceScopes
+	}
+
+	config, err := oauthConfig(scopes...)
+	if err != nil {
+		return "", err
+	}
+
+	var token *oauth2.Token
+	if device {
+		token, err = requestTokenFromDevice(ctx, config)
+	} else {
+		token, err = requestTokenFromWeb(ctx, config)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	store := activeTokenStore()
+	if err := store.save(token); err != nil {
+		return "", fmt.Errorf("unable to save token: %w", err)
+	}
+	SetClient(nil)
+	return store.String(), nil
+}
+
+// oauthConfig reads the OAuth client credentials file
+func oauthConfig(scopes ...string) (*oauth2.Config, error) {
+	credPath := CredentialsPath()
+	credentials, err := os.ReadFile(credPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read credentials file %s: %w\nSee README.md for setup instructions", credPath, err)
+	}
+
+	config, err := google.ConfigFromJSON(credentials, scopes...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse credentials: %w", err)
+	}
+
+	// The credentials file has no device endpoint
+	config.Endpoint.DeviceAuthURL = google.Endpoint.DeviceAuthURL
+	return config, nil
+}
+
+// GetSheetsService returns the authenticated Google Sheets service of this invocation
 func GetSheetsService(ctx context.Context) (*sheets.Service, error) {
-	client, err := GetClient(ctx)
+	shared.Lock()
+	defer shared.Unlock()
+	if shared.sheets != nil {
+		return shared.sheets, nil
+	}
+
+	client, err := sharedClient(ctx)
 	if err != nil {
 		return nil, err
 	}
+	// Wrap a copy: the shared client also serves Drive, which the metadata cache ignores
+	sheetsClient := *client
+	sheetsClient.Transport = helpers.CacheInvalidatingTransport(client.Transport)
 
-	service, err := sheets.NewService(ctx, option.WithHTTPClient(client))
+	service, err := sheets.NewService(ctx, option.WithHTTPClient(&sheetsClient))
 	if err != nil {
 		return nil, fmt.Errorf("unable to create Sheets service: %w", err)
 	}
 
+	shared.sheets = service
 	return service, nil
 }
 
-func getCredentialsPath() string {
-	home, err := os.UserHomeDir()
+// GetDriveService returns the authenticated Google Drive service of this invocation
+func GetDriveService(ctx context.Context) (*drive.Service, error) {
+	shared.Lock()
+	defer shared.Unlock()
+	if shared.drive != nil {
+		return shared.drive, nil
+	}
+
+	client, err := sharedClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := drive.NewService(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create Drive service: %w", err)
+	}
+
+	shared.drive = service
+	return service, nil
+}
+
+// serviceAccountKeyPath returns the service account key to use: --service-account, then
+// SPREADSHEET_MANAGER_SERVICE_ACCOUNT, then GOOGLE_APPLICATION_CREDENTIALS when it holds a
+// service account key (it may also hold gcloud user credentials, which are ignored)
+func serviceAccountKeyPath() string {
+	if ServiceAccountFile != "" {
+		return ServiceAccountFile
+	}
+	if path := os.Getenv(ServiceAccountEnv); path != "" {
+		return path
+	}
+	if path := os.Getenv(ApplicationCredentialsEnv); path != "" && isServiceAccountKey(path) {
+		return path
+	}
+	return ""
+}
+
+func isServiceAccountKey(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var key struct {
+		Type string `json:"type"`
+	}
+	return json.Unmarshal(data, &key) == nil && key.Type == "service_account"
+}
+
+// serviceAccountClient authenticates as the service account with a signed JWT, without any browser step
+func serviceAccountClient(ctx context.Context, keyPath string) (*http.Client, error) {
+	config, err := serviceAccountConfig(keyPath)
 	if err != nil {
-		return ""
+		return nil, err
+	}
+
+	return oauth2.NewClient(ctx, serviceAccountTokenSource(ctx, config)), nil
+}
+
+// impersonatedUser returns the Workspace user to act as: --impersonate or $SPREADSHEET_MANAGER_IMPERSONATE
+func impersonatedUser() string {
+	if Impersonate != "" {
+		return Impersonate
+	}
+	return os.Getenv(ImpersonateEnv)
+}
+
+// serviceAccountConfig loads the key; with an impersonated user, it becomes the JWT subject,
+// which is how domain-wide delegation is requested
+func serviceAccountConfig(keyPath string) (*jwt.Config, error) {
+	key, err := os.ReadFile(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read service account key %s: %w", keyPath, err)
+	}
+
+	config, err := google.JWTConfigFromJSON(key, DefaultScopes...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse service account key %s: %w", keyPath, err)
+	}
+	config.Subject = impersonatedUser()
+	return config, nil
+}
+
+func serviceAccountTokenSource(ctx context.Context, config *jwt.Config) oauth2.TokenSource {
+	if config.Subject == "" {
+		return config.TokenSource(ctx)
 	}
-	return filepath.Join(home, CredentialsDir)
+	return delegationTokenSource{source: config.TokenSource(ctx), subject: config.Subject}
+}
+
+// delegationTokenSource explains the usual cause of impersonation failures, which Google only
+// reports as "unauthorized_client"
+type delegationTokenSource struct {
+	source  oauth2.TokenSource
+	subject string
+}
+
+func (s delegationTokenSource) Token() (*oauth2.Token, error) {
+	token, err := s.source.Token()
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		return nil, fmt.Errorf("unable to impersonate %s (check that the service account client ID has domain-wide delegation for the Sheets and Drive scopes in the Admin console): %w", s.subject, err)
+	}
+	return token, err
+}
+
+// CredentialsPath returns the OAuth client file: --credentials, $SPREADSHEET_MANAGER_CREDENTIALS,
+// credentials.json of the active profile, or credentials.json in the config directory
+// (profiles usually share one OAuth client)
+func CredentialsPath() string {
+	if CredentialsFilePath == "" && os.Getenv(CredentialsEnv) == "" {
+		if dir := profileDir(ActiveProfile()); dir != "" {
+			path := filepath.Join(dir, CredentialsFile)
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+	return resolvePath(CredentialsFilePath, CredentialsEnv, CredentialsFile, LegacyCredentialsFile)
+}
+
+// TokenPath returns the stored OAuth token: --token, $SPREADSHEET_MANAGER_TOKEN,
+// or token.json of the active profile
+func TokenPath() string {
+	if TokenFilePath == "" && os.Getenv(TokenEnv) == "" {
+		return profileTokenPath(ActiveProfile())
+	}
+	return resolvePath(TokenFilePath, TokenEnv, TokenFile, LegacyTokenFile)
+}
+
+func profileTokenPath(name string) string {
+	if dir := profileDir(name); dir != "" {
+		return filepath.Join(dir, TokenFile)
+	}
+	return resolvePath("", "", TokenFile, LegacyTokenFile)
+}
+
+// resolvePath picks the flag value, then the environment variable, then the file in the config
+// directory. The pre-XDG ~/.credentials file is still used when only it exists.
+func resolvePath(flagValue, envName, name, legacyName string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if path := os.Getenv(envName); path != "" {
+		return path
+	}
+
+	path := filepath.Join(ConfigDir(), name)
+	if _, err := os.Stat(path); err != nil {
+		if home, err := os.UserHomeDir(); err == nil {
+			legacy := filepath.Join(home, LegacyCredentialsDir, legacyName)
+			if _, err := os.Stat(legacy); err == nil {
+				return legacy
+			}
+		}
+	}
+	return path
+}
+
+// ConfigDir returns the configuration directory: $XDG_CONFIG_HOME/spreadsheet-manager
+// (~/.config/spreadsheet-manager) on Linux, the platform equivalent elsewhere
+func ConfigDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ConfigDirName
+	}
+	return filepath.Join(dir, ConfigDirName)
 }
 
 func loadToken(path string) (*oauth2.Token, error) {
@@ -92,16 +424,50 @@ func loadToken(path string) (*oauth2.Token, error) {
 	return token, err
 }
 
+// requestTokenFromWeb runs the OAuth loopback flow: a callback server on an ephemeral
+// 127.0.0.1 port with its own mux, a random state checked on callback, and PKCE
 func requestTokenFromWeb(ctx context.Context, config *oauth2.Config) (*oauth2.Token, error) {
+	listener, err := net.Listen("tcp", CallbackServerAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start callback server: %w", err)
+	}
+
+	// Desktop OAuth clients accept any loopback port, so the config is copied with the one we got
+	flowConfig := *config
+	flowConfig.RedirectURL = fmt.Sprintf("http://%s/", listener.Addr().String())
+
+	state, err := randomState()
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
 	codeChan := make(chan string, 1)
 	errChan := make(chan error, 1)
+	// Only the first callback outcome matters; later ones must not block the handler
+	fail := func(err error) {
+		select {
+		case errChan <- err:
+		default:
+		}
+	}
 
-	server := &http.Server{Addr: CallbackServerPort}
-
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		code := r.URL.Query().Get("code")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("state") != state {
+			http.Error(w, "Invalid state parameter", http.StatusBadRequest)
+			return
+		}
+		if reason := query.Get("error"); reason != "" {
+			fail(fmt.Errorf("authorization denied: %s", reason))
+			http.Error(w, "Authorization denied", http.StatusForbidden)
+			return
+		}
+		code := query.Get("code")
 		if code == "" {
-			errChan <- fmt.Errorf("no authorization code in callback")
+			fail(fmt.Errorf("no authorization code in callback"))
 			http.Error(w, "No authorization code received", http.StatusBadRequest)
 			return
 		}
@@ -117,16 +483,21 @@ func requestTokenFromWeb(ctx context.Context, config *oauth2.Config) (*oauth2.To
 			</html>
 		`)
 
-		codeChan <- code
+		select {
+		case codeChan <- code:
+		default:
+		}
 	})
 
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: CallbackReadTimeout}
 	go func() {
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			errChan <- fmt.Errorf("failed to start server: %w", err)
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			fail(fmt.Errorf("callback server failed: %w", err))
 		}
 	}()
+	defer server.Shutdown(context.Background())
 
-	authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
+	authURL := flowConfig.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.S256ChallengeOption(verifier))
 	fmt.Printf("Go to the following link in your browser:\n%v\n\n", authURL)
 	fmt.Println("Waiting for authentication...")
 
@@ -134,16 +505,12 @@ func requestTokenFromWeb(ctx context.Context, config *oauth2.Config) (*oauth2.To
 	select {
 	case authCode = <-codeChan:
 	case err := <-errChan:
-		server.Shutdown(ctx)
 		return nil, err
 	case <-ctx.Done():
-		server.Shutdown(ctx)
 		return nil, fmt.Errorf("authentication cancelled")
 	}
 
-	server.Shutdown(ctx)
-
-	token, err := config.Exchange(ctx, authCode)
+	token, err := flowConfig.Exchange(ctx, authCode, oauth2.VerifierOption(verifier))
 	if err != nil {
 		return nil, fmt.Errorf("unable to retrieve token from web: %w", err)
 	}
@@ -151,6 +518,33 @@ func requestTokenFromWeb(ctx context.Context, config *oauth2.Config) (*oauth2.To
 	return token, nil
 }
 
+// requestTokenFromDevice runs the OAuth device authorization grant: the user opens a URL on
+// any device and types a code while this process polls for the token
+func requestTokenFromDevice(ctx context.Context, config *oauth2.Config) (*oauth2.Token, error) {
+	deviceAuth, err := config.DeviceAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to start device authorization: %w\nThe OAuth client must be of type \"TVs and Limited Input devices\"", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "On any device, go to:\n%s\n\nand enter the code: %s\n\n", deviceAuth.VerificationURI, deviceAuth.UserCode)
+	fmt.Fprintln(os.Stderr, "Waiting for authorization...")
+
+	token, err := config.DeviceAccessToken(ctx, deviceAuth)
+	if err != nil {
+		return nil, fmt.Errorf("device authorization failed: %w", err)
+	}
+	return token, nil
+}
+
+// randomState returns an unguessable OAuth state value
+func randomState() (string, error) {
+	buf := make([]byte, StateBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("unable to generate OAuth state: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
 func saveToken(path string, token *oauth2.Token) error {
 	fmt.Fprintf(os.Stderr, "Saving credentials to: %s\n", path)
 
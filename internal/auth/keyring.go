@@ -0,0 +1,136 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"golang.org/x/oauth2"
+)
+
+// Token storage values of "token_storage" in config.json
+const (
+	TokenStorageFile    = "file"
+	TokenStorageKeyring = "keyring"
+)
+
+// KeyringService names the tool's entries in the OS keychain
+const KeyringService = "spreadsheet-manager"
+
+var (
+	errKeyringNotFound    = errors.New("no token in the keyring")
+	errKeyringUnsupported = errors.New("no supported keyring on this system")
+)
+
+// tokenStore is where a profile keeps its token: a file, or the OS keychain
+// (macOS Keychain, Windows Credential Manager, Secret Service on Linux)
+type tokenStore struct {
+	path    string
+	account string
+}
+
+// activeTokenStore returns the store of the active profile. An explicit --token or
+// $SPREADSHEET_MANAGER_TOKEN always means file storage
+func activeTokenStore() tokenStore {
+	if TokenFilePath != "" || os.Getenv(TokenEnv) != "" {
+		return tokenStore{path: TokenPath()}
+	}
+	return profileTokenStore(ActiveProfile())
+}
+
+// profileTokenStore returns the store of a profile, following "token_storage" in config.json.
+// Without a usable keyring it warns and falls back to the token file
+func profileTokenStore(name string) tokenStore {
+	store := tokenStore{path: profileTokenPath(name)}
+
+	config, err := LoadConfig()
+	if err != nil || config.TokenStorage != TokenStorageKeyring {
+		return store
+	}
+	if err := keyringAvailable(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, using token file %s\n", err, store.path)
+		return store
+	}
+
+	store.account = name
+	return store
+}
+
+func (s tokenStore) String() string {
+	if s.account != "" {
+		return fmt.Sprintf("keyring:%s/%s", KeyringService, s.account)
+	}
+	return s.path
+}
+
+// load reads the token; a keyring store still reads a token file left from file storage
+func (s tokenStore) load() (*oauth2.Token, error) {
+	if s.account == "" {
+		return loadToken(s.path)
+	}
+
+	secret, err := keyringGet(s.account)
+	if errors.Is(err, errKeyringNotFound) {
+		return loadToken(s.path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	token := &oauth2.Token{}
+	err = json.Unmarshal([]byte(secret), token)
+	return token, err
+}
+
+// save writes the token. Once in the keyring, a leftover token file is deleted; if the
+// keyring refuses the token, it is written to the file instead
+func (s tokenStore) save(token *oauth2.Token) error {
+	if s.account == "" {
+		return saveToken(s.path, token)
+	}
+
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	if err := keyringSet(s.account, string(data)); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: unable to store token in the keyring (%v), using token file %s\n", err, s.path)
+		return saveToken(s.path, token)
+	}
+
+	fmt.Fprintf(os.Stderr, "Saving credentials to: %s\n", s)
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Warning: unable to delete token file %s: %v\n", s.path, err)
+	}
+	return nil
+}
+
+// exists reports whether a token is stored, without reading it
+func (s tokenStore) exists() bool {
+	if s.account != "" {
+		if _, err := keyringGet(s.account); err == nil {
+			return true
+		}
+	}
+	_, err := os.Stat(s.path)
+	return err == nil
+}
+
+// remove deletes the token from the keyring and any token file
+func (s tokenStore) remove() error {
+	found := false
+	if s.account != "" {
+		err := keyringDelete(s.account)
+		if err != nil && !errors.Is(err, errKeyringNotFound) {
+			return err
+		}
+		found = err == nil
+	}
+
+	err := os.Remove(s.path)
+	if err == nil || (found && os.IsNotExist(err)) {
+		return nil
+	}
+	return err
+}
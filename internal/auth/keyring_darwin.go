@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// macOS Keychain through the security tool; exit status 44 means the item does not exist
+const keychainNotFoundStatus = 44
+
+func keyringAvailable() error {
+	if _, err := exec.LookPath("security"); err != nil {
+		return errKeyringUnsupported
+	}
+	return nil
+}
+
+func keyringGet(account string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", KeyringService, "-a", account, "-w").Output()
+	if err != nil {
+		return "", keychainError(err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+// keyringSet passes the secret on stdin (hex encoded with -X) so it never shows in the process list
+func keyringSet(account, secret string) error {
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %q -a %q -X %s\n",
+		KeyringService, account, hex.EncodeToString([]byte(secret))))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("security: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func keyringDelete(account string) error {
+	err := exec.Command("security", "delete-generic-password", "-s", KeyringService, "-a", account).Run()
+	return keychainError(err)
+}
+
+func keychainError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == keychainNotFoundStatus {
+		return errKeyringNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("security: %w", err)
+	}
+	return nil
+}
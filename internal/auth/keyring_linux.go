@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Secret Service (GNOME Keyring, KWallet) through secret-tool from libsecret
+
+func keyringAvailable() error {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return errKeyringUnsupported
+	}
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return errors.New("no D-Bus session for the Secret Service")
+	}
+	return nil
+}
+
+// keyringGet relies on secret-tool printing nothing and exiting with 1 when there is no match
+func keyringGet(account string) (string, error) {
+	out, err := exec.Command("secret-tool", "lookup", "service", KeyringService, "account", account).Output()
+	if len(out) == 0 {
+		var exitErr *exec.ExitError
+		if err == nil || errors.As(err, &exitErr) {
+			return "", errKeyringNotFound
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("secret-tool: %w", err)
+	}
+	return string(out), nil
+}
+
+func keyringSet(account, secret string) error {
+	cmd := exec.Command("secret-tool", "store", "--label", KeyringService+" ("+account+")",
+		"service", KeyringService, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("secret-tool: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func keyringDelete(account string) error {
+	if _, err := keyringGet(account); err != nil {
+		return err
+	}
+	if err := exec.Command("secret-tool", "clear", "service", KeyringService, "account", account).Run(); err != nil {
+		return fmt.Errorf("secret-tool: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+//go:build !darwin && !linux && !windows
+
+package auth
+
+func keyringAvailable() error {
+	return errKeyringUnsupported
+}
+
+func keyringGet(account string) (string, error) {
+	return "", errKeyringUnsupported
+}
+
+func keyringSet(account, secret string) error {
+	return errKeyringUnsupported
+}
+
+func keyringDelete(account string) error {
+	return errKeyringUnsupported
+}
@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Config is the optional config.json of the config directory
+type Config struct {
+	DefaultProfile string `json:"default_profile,omitempty"`
+	TokenStorage   string `json:"token_storage,omitempty"`
+}
+
+// ProfileInfo describes one auth profile
+type ProfileInfo struct {
+	Name     string `json:"name"`
+	Active   bool   `json:"active"`
+	HasToken bool   `json:"has_token"`
+	Token    string `json:"token"`
+}
+
+// LoadConfig reads config.json from the config directory; a missing file is an empty config
+func LoadConfig() (*Config, error) {
+	config := &Config{}
+	data, err := os.ReadFile(filepath.Join(ConfigDir(), ConfigFile))
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config: %w", err)
+	}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", filepath.Join(ConfigDir(), ConfigFile), err)
+	}
+	return config, nil
+}
+
+// ActiveProfile returns the profile in use: --profile, $SPREADSHEET_MANAGER_PROFILE,
+// default_profile from config.json, or "default"
+func ActiveProfile() string {
+	if Profile != "" {
+		return Profile
+	}
+	if name := os.Getenv(ProfileEnv); name != "" {
+		return name
+	}
+	if config, err := LoadConfig(); err == nil && config.DefaultProfile != "" {
+		return config.DefaultProfile
+	}
+	return DefaultProfile
+}
+
+// ValidateProfile rejects profile names that cannot be used as a directory name
+func ValidateProfile(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid profile name '%s'", name)
+	}
+	return nil
+}
+
+// profileDir returns the directory of a named profile, or "" for the default profile,
+// whose files live directly in the config directory
+func profileDir(name string) string {
+	if name == DefaultProfile || ValidateProfile(name) != nil {
+		return ""
+	}
+	return filepath.Join(ConfigDir(), ProfilesDir, name)
+}
+
+// ListProfiles returns the default profile and every profile directory, sorted by name
+func ListProfiles() ([]ProfileInfo, error) {
+	names := []string{DefaultProfile}
+	entries, err := os.ReadDir(filepath.Join(ConfigDir(), ProfilesDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("unable to list profiles: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != DefaultProfile {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names[1:])
+
+	active := ActiveProfile()
+	profiles := make([]ProfileInfo, 0, len(names))
+	for _, name := range names {
+		store := profileTokenStore(name)
+		profiles = append(profiles, ProfileInfo{
+			Name:     name,
+			Active:   name == active,
+			HasToken: store.exists(),
+			Token:    store.String(),
+		})
+	}
+	return profiles, nil
+}
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit is bound to the --rate-limit flag: API requests per minute, 0 for no limit
+var RateLimit int
+
+// QuotaUsage counts the API requests of this invocation, as the per-minute quotas see
+// them: requests actually sent, including retries, by API and read or write
+type QuotaUsage struct {
+	Requests  map[string]map[string]int
+	Retries   int
+	Throttled time.Duration
+}
+
+var quota = struct {
+	sync.Mutex
+	usage QuotaUsage
+}{usage: QuotaUsage{Requests: map[string]map[string]int{}}}
+
+// Usage returns a copy of the requests counted so far
+func Usage() QuotaUsage {
+	quota.Lock()
+	defer quota.Unlock()
+
+	usage := quota.usage
+	usage.Requests = map[string]map[string]int{}
+	for api, counts := range quota.usage.Requests {
+		usage.Requests[api] = map[string]int{}
+		for kind, count := range counts {
+			usage.Requests[api][kind] = count
+		}
+	}
+	return usage
+}
+
+func recordRequest(req *http.Request) {
+	api, kind := classifyRequest(req)
+
+	quota.Lock()
+	defer quota.Unlock()
+	if quota.usage.Requests[api] == nil {
+		quota.usage.Requests[api] = map[string]int{}
+	}
+	quota.usage.Requests[api][kind]++
+}
+
+func recordRetry() {
+	quota.Lock()
+	defer quota.Unlock()
+	quota.usage.Retries++
+}
+
+func recordThrottle(wait time.Duration) {
+	quota.Lock()
+	defer quota.Unlock()
+	quota.usage.Throttled += wait
+}
+
+// classifyRequest maps a request to its API and quota kind. Sheets counts the POST lookups
+// (getByDataFilter, developerMetadata:search) as reads
+func classifyRequest(req *http.Request) (api, kind string) {
+	path := req.URL.Path
+	switch {
+	case req.URL.Host == "sheets.googleapis.com":
+		api = "sheets"
+	case strings.Contains(path, "/drive/"):
+		api = "drive"
+	default:
+		api = "other"
+	}
+
+	kind = "write"
+	if req.Method == http.MethodGet || req.Method == http.MethodHead ||
+		strings.HasSuffix(path, "ByDataFilter") || strings.HasSuffix(path, "developerMetadata:search") {
+		kind = "read"
+	}
+	return api, kind
+}
+
+// rateLimiter is a token bucket refilled at RateLimit per minute, holding up to one second of
+// requests so short bursts stay within the per-minute quota
+type rateLimiter struct {
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+var limiter rateLimiter
+
+// wait blocks until a request may be sent. Tokens are reserved up front, so concurrent
+// callers queue up in order instead of all waking at once
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if RateLimit <= 0 {
+		return nil
+	}
+	perSecond := float64(RateLimit) / 60
+	capacity := max(1, perSecond)
+
+	l.mu.Lock()
+	now := time.Now()
+	if l.last.IsZero() {
+		l.tokens = capacity
+	} else {
+		l.tokens = min(capacity, l.tokens+now.Sub(l.last).Seconds()*perSecond)
+	}
+	l.last = now
+	l.tokens--
+	deficit := -l.tokens
+	l.mu.Unlock()
+
+	if deficit <= 0 {
+		return nil
+	}
+
+	wait := time.Duration(deficit / perSecond * float64(time.Second))
+	recordThrottle(wait)
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// meteredTransport applies the rate limit and counts every request it sends
+type meteredTransport struct {
+	base http.RoundTripper
+}
+
+func newMeteredTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return meteredTransport{base: base}
+}
+
+func (t meteredTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := limiter.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	recordRequest(req)
+	return t.base.RoundTrip(req)
+}
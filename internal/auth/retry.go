@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"spreadsheet-manager/internal/helpers"
+)
+
+const (
+	DefaultMaxRetries   = 5
+	DefaultRetryMaxWait = 64 * time.Second
+	RetryBaseWait       = time.Second
+)
+
+// Values of the --max-retries and --retry-max-wait flags
+var (
+	MaxRetries   = DefaultMaxRetries
+	RetryMaxWait = DefaultRetryMaxWait
+)
+
+// retryTransport retries requests that failed with 429, 5xx, or a 403 rate limit error (Drive
+// reports quotas that way), waiting for Retry-After when the server sends it and for a jittered
+// exponential backoff otherwise. Requests whose body cannot be replayed (streamed uploads) are
+// sent once.
+//
+// A quota rejection means nothing was applied, so it is always retried. A 5xx may come after
+// the server applied the write, so it is only retried for requests that give the same result
+// when sent twice (see isIdempotent): retrying values:append or an insertDimension would
+// duplicate rows
+type retryTransport struct {
+	base http.RoundTripper
+}
+
+func newRetryTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return retryTransport{base: base}
+}
+
+func (t retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || attempt >= MaxRetries || !isRetryable(req, resp) {
+			return resp, err
+		}
+		if req.Body != nil && req.GetBody == nil {
+			return resp, nil
+		}
+
+		wait := retryWait(resp, attempt)
+		recordRetry()
+		if !helpers.Quiet {
+			fmt.Fprintf(os.Stderr, "Retrying in %s after %s (attempt %d/%d)\n", wait.Round(100*time.Millisecond), resp.Status, attempt+1, MaxRetries)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func isRetryable(req *http.Request, resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(req)
+	case http.StatusForbidden:
+		// Read the error reason, then put the body back for the caller
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		return err == nil && (bytes.Contains(body, []byte("rateLimitExceeded")) || bytes.Contains(body, []byte("userRateLimitExceeded")))
+	}
+	return false
+}
+
+// idempotentSuffixes are the POST methods of the Sheets API that read, or overwrite and clear
+// fixed ranges. spreadsheets:batchUpdate is not one of them: it can insert rows or add sheets
+var idempotentSuffixes = []string{
+	"values:batchUpdate", "values:batchUpdateByDataFilter",
+	"values:batchClear", "values:batchClearByDataFilter", ":clear",
+	"values:batchGetByDataFilter", ":getByDataFilter", "developerMetadata:search",
+}
+
+// isIdempotent reports whether sending the request twice has the same effect as sending it once
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		for _, suffix := range idempotentSuffixes {
+			if strings.HasSuffix(req.URL.Path, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// retryWait honors Retry-After (seconds or HTTP date), and otherwise waits a random time up to
+// RetryBaseWait * 2^attempt ("full jitter"); both are capped at RetryMaxWait
+func retryWait(resp *http.Response, attempt int) time.Duration {
+	var wait time.Duration
+	if value := strings.TrimSpace(resp.Header.Get("Retry-After")); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			wait = time.Duration(seconds) * time.Second
+		} else if date, err := http.ParseTime(value); err == nil {
+			wait = time.Until(date)
+		}
+	}
+	if wait <= 0 {
+		backoff := RetryBaseWait << min(attempt, 16)
+		wait = time.Duration(rand.Int64N(int64(backoff))) + time.Duration(rand.Int64N(int64(RetryBaseWait)))
+	}
+	return min(wait, RetryMaxWait)
+}
@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// Status describes the credentials commands run with
+type Status struct {
+	Method        string    `json:"method"`
+	Profile       string    `json:"profile,omitempty"`
+	Source        string    `json:"source"`
+	Email         string    `json:"email,omitempty"`
+	Impersonating string    `json:"impersonating,omitempty"`
+	Scopes        []string  `json:"scopes"`
+	Expiry        time.Time `json:"expiry"`
+}
+
+// CurrentStatus checks the active credentials without starting an OAuth flow: the token is
+// refreshed if needed and its granted scopes are read from Google's tokeninfo endpoint
+func CurrentStatus(ctx context.Context) (*Status, error) {
+	var source oauth2.TokenSource
+	status := &Status{}
+
+	if keyPath := serviceAccountKeyPath(); keyPath != "" {
+		config, err := serviceAccountConfig(keyPath)
+		if err != nil {
+			return nil, err
+		}
+		source = serviceAccountTokenSource(ctx, config)
+		status.Method = "service_account"
+		status.Source = keyPath
+		status.Email = config.Email
+		status.Impersonating = config.Subject
+	} else {
+		if impersonatedUser() != "" {
+			return nil, fmt.Errorf("--impersonate requires a service account key (--service-account)")
+		}
+		if err := ValidateProfile(ActiveProfile()); err != nil {
+			return nil, err
+		}
+
+		store := activeTokenStore()
+		token, err := store.load()
+		if err != nil {
+			return nil, fmt.Errorf("not signed in (no token at %s): run auth login", store)
+		}
+
+		config, err := oauthConfig(DefaultScopes...)
+		if err != nil {
+			return nil, err
+		}
+		source = config.TokenSource(ctx, token)
+		status.Method = "oauth"
+		status.Profile = ActiveProfile()
+		status.Source = store.String()
+	}
+
+	token, err := source.Token()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get access token: %w", err)
+	}
+	status.Expiry = token.Expiry
+
+	info, err := tokenInfo(ctx, token.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	status.Scopes = strings.Fields(info.Scope)
+	if status.Email == "" {
+		status.Email = info.Email
+	}
+
+	return status, nil
+}
+
+type tokenInfoResponse struct {
+	Scope string `json:"scope"`
+	Email string `json:"email"`
+}
+
+func tokenInfo(ctx context.Context, accessToken string) (*tokenInfoResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, TokenInfoURL+"?access_token="+url.QueryEscape(accessToken), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get token info: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unable to get token info: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	info := &tokenInfoResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(info); err != nil {
+		return nil, fmt.Errorf("invalid token info: %w", err)
+	}
+	return info, nil
+}
+
+// Logout deletes the stored token of the active profile. With revoke, the token is first
+// revoked at Google so copies of it stop working too; a token Google no longer knows
+// (already revoked or expired) is reported rather than treated as an error
+func Logout(ctx context.Context, revoke bool) (string, bool, error) {
+	if err := ValidateProfile(ActiveProfile()); err != nil {
+		return "", false, err
+	}
+
+	store := activeTokenStore()
+	token, err := store.load()
+	if err != nil {
+		return "", false, fmt.Errorf("not signed in (no token at %s)", store)
+	}
+
+	revoked := false
+	if revoke {
+		revoked, err = revokeToken(ctx, token)
+		if err != nil {
+			return "", false, err
+		}
+	}
+
+	if err := store.remove(); err != nil {
+		return "", revoked, fmt.Errorf("unable to delete token: %w", err)
+	}
+	SetClient(nil)
+	return store.String(), revoked, nil
+}
+
+func revokeToken(ctx context.Context, token *oauth2.Token) (bool, error) {
+	// Revoking the refresh token also revokes the access tokens issued from it
+	value := token.RefreshToken
+	if value == "" {
+		value = token.AccessToken
+	}
+
+	form := url.Values{"token": {value}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, RevokeURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("unable to revoke token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusBadRequest:
+		fmt.Fprintln(os.Stderr, "Warning: Google did not recognize the token (already revoked or expired)")
+		return false, nil
+	default:
+		body, _ := io.ReadAll(resp.Body)
+		return false, fmt.Errorf("unable to revoke token: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage authentication",
+}
+
+var authLoginDevice bool
+
+var authLoginCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Sign in with Google and store a new token",
+		Long: `Sign in with Google and store a new token, replacing the current one.
+
+By default a browser is opened on a local callback URL. With --device, a URL
+and a code are printed instead, to be entered on any other device (for SSH-only
+machines). The device flow requires a "TVs and Limited Input devices" OAuth
+client, and Google limits it to the drive.file scope: only spreadsheets created
+or opened by this tool are reachable with such a token.`,
+		Args: cobra.NoArgs,
+		RunE: runAuthLogin,
+	}
+	cmd.Flags().BoolVar(&authLoginDevice, "device", false, "Use the device code flow (no browser on this machine)")
+	return cmd
+}()
+
+func runAuthLogin(cmd *cobra.Command, args []string) error {
+	tokenPath, err := auth.Login(context.Background(), authLoginDevice)
+	if err != nil {
+		return err
+	}
+
+	return helpers.PrintJSON(map[string]string{
+		"status":  "success",
+		"profile": auth.ActiveProfile(),
+		"token":   tokenPath,
+	})
+}
+
+var authListProfilesCmd = &cobra.Command{
+	Use:   "list-profiles",
+	Short: "List auth profiles and whether each has a stored token",
+	Long: `List auth profiles and whether each has a stored token.
+
+The "default" profile keeps its files directly in the config directory; other
+profiles live in profiles/<name>/ and are created by "auth login --profile <name>".
+A profile uses its own credentials.json when present, and the shared one otherwise.
+The profile used when --profile is not given is set with "default_profile" in
+config.json of the config directory.`,
+	Args: cobra.NoArgs,
+	RunE: runAuthListProfiles,
+}
+
+func runAuthListProfiles(cmd *cobra.Command, args []string) error {
+	profiles, err := auth.ListProfiles()
+	if err != nil {
+		return err
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":   "success",
+		"active":   auth.ActiveProfile(),
+		"count":    len(profiles),
+		"profiles": profiles,
+	})
+}
+
+var authLogoutRevoke bool
+
+var authLogoutCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Delete the stored token of the active profile",
+		Long: `Delete the stored token of the active profile.
+
+With --revoke, the token is also revoked at Google, so copies of it stop
+working and the app disappears from the account's third-party access list.
+Service account keys are not affected.`,
+		Args: cobra.NoArgs,
+		RunE: runAuthLogout,
+	}
+	cmd.Flags().BoolVar(&authLogoutRevoke, "revoke", false, "Revoke the token at Google before deleting it")
+	return cmd
+}()
+
+func runAuthLogout(cmd *cobra.Command, args []string) error {
+	tokenPath, revoked, err := auth.Logout(context.Background(), authLogoutRevoke)
+	if err != nil {
+		return err
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":  "success",
+		"profile": auth.ActiveProfile(),
+		"deleted": tokenPath,
+		"revoked": revoked,
+	})
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:     "status",
+	Aliases: []string{"whoami"},
+	Short:   "Print the signed-in account and the scopes granted to its token",
+	Long: `Print the signed-in account and the scopes granted to its token.
+
+The token is refreshed if needed, but no sign-in is started when there is none.`,
+	Args: cobra.NoArgs,
+	RunE: runAuthStatus,
+}
+
+func runAuthStatus(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	status, err := auth.CurrentStatus(ctx)
+	if err != nil {
+		return err
+	}
+
+	result := map[string]interface{}{
+		"status": "success",
+		"method": status.Method,
+		"source": status.Source,
+		"scopes": status.Scopes,
+		"expiry": status.Expiry,
+	}
+	if status.Profile != "" {
+		result["profile"] = status.Profile
+	}
+	if status.Impersonating != "" {
+		result["impersonating"] = status.Impersonating
+	}
+
+	// Tokens carry no email without the email scope, but Drive knows the user
+	if status.Email == "" {
+		driveService, err := auth.GetDriveService(ctx)
+		if err != nil {
+			return err
+		}
+		about, err := driveService.About.Get().Fields("user(displayName,emailAddress)").Do()
+		if err != nil {
+			return fmt.Errorf("unable to get user: %w", err)
+		}
+		status.Email = about.User.EmailAddress
+		result["name"] = about.User.DisplayName
+	}
+	result["email"] = status.Email
+
+	return helpers.PrintJSON(result)
+}
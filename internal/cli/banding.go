@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+const DefaultBandingPalette = "gray"
+
+// bandingPalette holds the hex colors of a built-in banding theme
+type bandingPalette struct {
+	header string
+	first  string
+	second string
+}
+
+var bandingPalettes = map[string]bandingPalette{
+	"blue":   {header: "#4285f4", first: "#ffffff", second: "#e8f0fe"},
+	"gray":   {header: "#bdbdbd", first: "#ffffff", second: "#f3f3f3"},
+	"green":  {header: "#34a853", first: "#ffffff", second: "#e6f4ea"},
+	"orange": {header: "#f4b400", first: "#ffffff", second: "#fef7e0"},
+	"red":    {header: "#ea4335", first: "#ffffff", second: "#fce8e6"},
+}
+
+var (
+	addBandingPalette     string
+	addBandingHeaderColor string
+	addBandingFirstColor  string
+	addBandingSecondColor string
+	addBandingFooterColor string
+	addBandingNoHeader    bool
+)
+
+var addBandingCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-banding <spreadsheet-id> <sheet-name> <range>",
+		Short: "Apply alternating row colors to a range",
+		Args:  cobra.ExactArgs(3),
+		RunE:  runAddBanding,
+	}
+	cmd.Flags().StringVar(&addBandingPalette, "palette", DefaultBandingPalette, "Built-in palette ("+strings.Join(bandingPaletteNames(), ", ")+")")
+	cmd.Flags().StringVar(&addBandingHeaderColor, "header-color", "", "Header row color (hex, overrides palette)")
+	cmd.Flags().StringVar(&addBandingFirstColor, "first-color", "", "First band color (hex, overrides palette)")
+	cmd.Flags().StringVar(&addBandingSecondColor, "second-color", "", "Second band color (hex, overrides palette)")
+	cmd.Flags().StringVar(&addBandingFooterColor, "footer-color", "", "Footer row color (hex)")
+	cmd.Flags().BoolVar(&addBandingNoHeader, "no-header", false, "Do not color the first row as a header")
+	return cmd
+}()
+
+func runAddBanding(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	rangeA1 := args[2]
+
+	palette, ok := bandingPalettes[strings.ToLower(addBandingPalette)]
+	if !ok {
+		return fmt.Errorf("unknown palette '%s' (available: %s)", addBandingPalette, strings.Join(bandingPaletteNames(), ", "))
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	gridRange, err := helpers.ParseGridRange(sheetID, rangeA1)
+	if err != nil {
+		return err
+	}
+
+	rowProps := &sheets.BandingProperties{
+		FirstBandColorStyle:  colorStyle(firstNonEmpty(addBandingFirstColor, palette.first)),
+		SecondBandColorStyle: colorStyle(firstNonEmpty(addBandingSecondColor, palette.second)),
+	}
+	if !addBandingNoHeader {
+		rowProps.HeaderColorStyle = colorStyle(firstNonEmpty(addBandingHeaderColor, palette.header))
+	}
+	if addBandingFooterColor != "" {
+		rowProps.FooterColorStyle = colorStyle(addBandingFooterColor)
+	}
+
+	req := &sheets.Request{
+		AddBanding: &sheets.AddBandingRequest{
+			BandedRange: &sheets.BandedRange{
+				Range:         gridRange,
+				RowProperties: rowProps,
+			},
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	resp, err := service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to add banding: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"status": "success",
+		"range":  rangeA1,
+	}
+	if len(resp.Replies) > 0 && resp.Replies[0].AddBanding != nil && resp.Replies[0].AddBanding.BandedRange != nil {
+		result["banded_range_id"] = resp.Replies[0].AddBanding.BandedRange.BandedRangeId
+	}
+
+	return helpers.PrintJSON(result)
+}
+
+var listBandingCmd = &cobra.Command{
+	Use:   "list-banding <spreadsheet-id> [sheet-name]",
+	Short: "List banded ranges",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  runListBanding,
+}
+
+func runListBanding(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetID).
+		Fields("sheets(properties(sheetId,title,index),bandedRanges)").Do()
+	if err != nil {
+		return fmt.Errorf("unable to get spreadsheet: %w", err)
+	}
+
+	var onlySheetID *int64
+	if len(args) > 1 {
+		props, err := helpers.FindSheet(spreadsheet, args[1])
+		if err != nil {
+			return err
+		}
+		onlySheetID = &props.SheetId
+	}
+
+	bandings := []map[string]interface{}{}
+	for _, sheet := range spreadsheet.Sheets {
+		if onlySheetID != nil && sheet.Properties.SheetId != *onlySheetID {
+			continue
+		}
+		for _, banded := range sheet.BandedRanges {
+			bandings = append(bandings, map[string]interface{}{
+				"banded_range_id": banded.BandedRangeId,
+				"sheet_name":      sheet.Properties.Title,
+				"range":           helpers.GridRangeToA1(banded.Range),
+			})
+		}
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":   "success",
+		"bandings": bandings,
+	})
+}
+
+var deleteBandingCmd = &cobra.Command{
+	Use:   "delete-banding <spreadsheet-id> <banded-range-id>",
+	Short: "Remove alternating colors from a banded range",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runDeleteBanding,
+}
+
+func runDeleteBanding(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	bandedRangeID, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid banded range ID: %s", args[1])
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.Request{
+		DeleteBanding: &sheets.DeleteBandingRequest{
+			BandedRangeId: bandedRangeID,
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to delete banding: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":          "success",
+		"banded_range_id": bandedRangeID,
+	})
+}
+
+func bandingPaletteNames() []string {
+	names := make([]string, 0, len(bandingPalettes))
+	for name := range bandingPalettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func colorStyle(hexColor string) *sheets.ColorStyle {
+	return &sheets.ColorStyle{RgbColor: helpers.ParseColor(hexColor)}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
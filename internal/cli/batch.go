@@ -0,0 +1,333 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+// batchOperation is one entry of a batch plan. Keys follow the arguments and flags of the
+// command of the same name; "range" holds the cells, rows or columns the command takes.
+// "spreadsheet" sends the operation to another spreadsheet than the one given as argument
+type batchOperation struct {
+	Command     string `json:"command"`
+	Spreadsheet string `json:"spreadsheet"`
+	Sheet       string `json:"sheet"`
+	Range       string `json:"range"`
+	Type        string `json:"type"`
+	Pattern     string `json:"pattern"`
+	Rows        *int   `json:"rows"`
+	Cols        *int   `json:"cols"`
+	Pixels      int64  `json:"pixels"`
+	cellStyle
+}
+
+// batchCompilers turn a batch operation on a resolved sheet into requests
+var batchCompilers = map[string]func(op batchOperation, sheetID int64) ([]*sheets.Request, error){
+	"auto-resize-columns": func(op batchOperation, sheetID int64) ([]*sheets.Request, error) {
+		dimRange := &sheets.DimensionRange{SheetId: sheetID, Dimension: MajorDimensionColumns}
+		if op.Range != "" {
+			var err error
+			if dimRange, err = parseDimensionRange(sheetID, MajorDimensionColumns, op.Range); err != nil {
+				return nil, err
+			}
+		}
+		return []*sheets.Request{{AutoResizeDimensions: &sheets.AutoResizeDimensionsRequest{Dimensions: dimRange}}}, nil
+	},
+	"format-cells": func(op batchOperation, sheetID int64) ([]*sheets.Request, error) {
+		if op.Type == "" {
+			return nil, fmt.Errorf("\"type\" is required")
+		}
+		gridRange, err := boundedGridRange(sheetID, op.Range)
+		if err != nil {
+			return nil, err
+		}
+		return []*sheets.Request{numberFormatRequest(gridRange, op.Type, op.Pattern)}, nil
+	},
+	"freeze": func(op batchOperation, sheetID int64) ([]*sheets.Request, error) {
+		if op.Rows == nil && op.Cols == nil {
+			return nil, fmt.Errorf("at least one of \"rows\" or \"cols\" is required")
+		}
+		return []*sheets.Request{freezeRequest(sheetID, op.Rows, op.Cols)}, nil
+	},
+	"hide-columns": batchDimensionHidden(MajorDimensionColumns, true),
+	"hide-rows":    batchDimensionHidden(MajorDimensionRows, true),
+	"merge-cells": func(op batchOperation, sheetID int64) ([]*sheets.Request, error) {
+		gridRange, err := helpers.ParseGridRange(sheetID, op.Range)
+		if err != nil {
+			return nil, err
+		}
+		mergeType := op.Type
+		if mergeType == "" {
+			mergeType = MergeTypeAll
+		}
+		return []*sheets.Request{{MergeCells: &sheets.MergeCellsRequest{Range: gridRange, MergeType: mergeType}}}, nil
+	},
+	"set-column-width": batchDimensionSize(MajorDimensionColumns),
+	"set-row-height":   batchDimensionSize(MajorDimensionRows),
+	"show-columns":     batchDimensionHidden(MajorDimensionColumns, false),
+	"show-rows":        batchDimensionHidden(MajorDimensionRows, false),
+	"style-cells": func(op batchOperation, sheetID int64) ([]*sheets.Request, error) {
+		if op.TextRotation != nil && op.Vertical {
+			return nil, fmt.Errorf("\"text_rotation\" and \"vertical\" are mutually exclusive")
+		}
+		gridRange, err := boundedGridRange(sheetID, op.Range)
+		if err != nil {
+			return nil, err
+		}
+		cellFormat, fields := buildCellFormat(op.cellStyle)
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("no style options provided")
+		}
+		return []*sheets.Request{cellFormatRequest(gridRange, cellFormat, fields)}, nil
+	},
+	"unmerge-cells": func(op batchOperation, sheetID int64) ([]*sheets.Request, error) {
+		gridRange, err := helpers.ParseGridRange(sheetID, op.Range)
+		if err != nil {
+			return nil, err
+		}
+		return []*sheets.Request{{UnmergeCells: &sheets.UnmergeCellsRequest{Range: gridRange}}}, nil
+	},
+}
+
+func batchDimensionSize(dimension string) func(op batchOperation, sheetID int64) ([]*sheets.Request, error) {
+	return func(op batchOperation, sheetID int64) ([]*sheets.Request, error) {
+		if op.Pixels <= 0 {
+			return nil, fmt.Errorf("\"pixels\" must be positive")
+		}
+		dimRange, err := parseDimensionRange(sheetID, dimension, op.Range)
+		if err != nil {
+			return nil, err
+		}
+		return []*sheets.Request{dimensionSizeRequest(dimRange, op.Pixels)}, nil
+	}
+}
+
+func batchDimensionHidden(dimension string, hidden bool) func(op batchOperation, sheetID int64) ([]*sheets.Request, error) {
+	return func(op batchOperation, sheetID int64) ([]*sheets.Request, error) {
+		dimRange, err := parseDimensionRange(sheetID, dimension, op.Range)
+		if err != nil {
+			return nil, err
+		}
+		return []*sheets.Request{dimensionHiddenRequest(dimRange, hidden)}, nil
+	}
+}
+
+// batchGroup holds the operations of a plan that go to one spreadsheet, in plan order
+type batchGroup struct {
+	spreadsheetID string
+	operations    []int
+	requests      []*sheets.Request
+}
+
+// compile resolves the sheets of the group's operations and builds its requests
+func (g *batchGroup) compile(service *sheets.Service, operations []batchOperation) error {
+	spreadsheet, err := helpers.GetSpreadsheetSheets(service, g.spreadsheetID)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve spreadsheet %s: %w", g.spreadsheetID, err)
+	}
+
+	for _, i := range g.operations {
+		op := operations[i]
+		sheet, err := helpers.FindSheet(spreadsheet, op.Sheet)
+		if err != nil {
+			return fmt.Errorf("operation %d (%s): %w", i+1, op.Command, err)
+		}
+
+		compiled, err := batchCompilers[op.Command](op, sheet.SheetId)
+		if err != nil {
+			return fmt.Errorf("operation %d (%s): %w", i+1, op.Command, err)
+		}
+		g.requests = append(g.requests, compiled...)
+	}
+	return nil
+}
+
+var (
+	batchDryRun      bool
+	batchConcurrency int
+)
+
+var batchCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "batch <spreadsheet-id> <plan.json|->",
+		Short: "Run a list of formatting operations as a single batch update",
+		Long: `Run a list of formatting operations as a single batch update ("-" reads the plan
+from stdin). All operations succeed or fail together, in one API call.
+
+The plan is a JSON array; each operation names a command and takes the
+arguments and flags of that command as keys, with "range" for the cells,
+rows or columns:
+
+  [
+    {"command": "format-cells", "sheet": "Sheet1", "range": "B2:B100", "type": "CURRENCY"},
+    {"command": "style-cells", "sheet": "Sheet1", "range": "A1:D1", "bold": true, "bg_color": "#eeeeee"},
+    {"command": "freeze", "sheet": "Sheet1", "rows": 1},
+    {"command": "set-column-width", "sheet": "Sheet1", "range": "A:D", "pixels": 150}
+  ]
+
+Supported commands: ` + strings.Join(batchCommandNames(), ", ") + `.
+Style keys are bg_color, font_color, font_size, bold, italic, underline,
+strikethrough, font_family, h_align, v_align, wrap, text_rotation and vertical.
+
+An operation with a "spreadsheet" key goes to that spreadsheet instead. Each
+spreadsheet gets its own batch update, up to --concurrency at a time, so
+operations only succeed or fail together within one spreadsheet. Nothing is
+sent until the whole plan compiles.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runBatch,
+	}
+	cmd.Flags().BoolVar(&batchDryRun, "dry-run", false, "Print the compiled batch request without sending it")
+	cmd.Flags().IntVar(&batchConcurrency, "concurrency", DefaultConcurrency, "Spreadsheets updated in parallel when the plan targets several")
+	return cmd
+}()
+
+func batchCommandNames() []string {
+	names := make([]string, 0, len(batchCompilers))
+	for name := range batchCompilers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func runBatch(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	planPath := args[1]
+
+	var data []byte
+	var err error
+	if planPath == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(planPath)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read plan: %w", err)
+	}
+
+	var operations []batchOperation
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&operations); err != nil {
+		return fmt.Errorf("invalid plan: %w", err)
+	}
+	if len(operations) == 0 {
+		return fmt.Errorf("plan has no operations")
+	}
+	if batchConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+
+	var groups []*batchGroup
+	groupOf := map[string]*batchGroup{}
+	for i, op := range operations {
+		if _, ok := batchCompilers[op.Command]; !ok {
+			return fmt.Errorf("operation %d: unsupported command '%s' (supported: %s)", i+1, op.Command, strings.Join(batchCommandNames(), ", "))
+		}
+		if op.Sheet == "" {
+			return fmt.Errorf("operation %d (%s): \"sheet\" is required", i+1, op.Command)
+		}
+
+		id := op.Spreadsheet
+		if id == "" {
+			id = spreadsheetID
+		}
+		group, ok := groupOf[id]
+		if !ok {
+			group = &batchGroup{spreadsheetID: id}
+			groupOf[id] = group
+			groups = append(groups, group)
+		}
+		group.operations = append(group.operations, i)
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, len(groups))
+	helpers.RunParallel(batchConcurrency, len(groups), func(i int) {
+		errs[i] = groups[i].compile(service, operations)
+	})
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(groups) == 1 {
+		batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+			Requests: groups[0].requests,
+		}
+
+		if batchDryRun {
+			return helpers.PrintJSON(batchReq)
+		}
+
+		_, err = service.Spreadsheets.BatchUpdate(groups[0].spreadsheetID, batchReq).Do()
+		if err != nil {
+			return fmt.Errorf("unable to run batch: %w", err)
+		}
+
+		return helpers.PrintJSON(map[string]interface{}{
+			"status":     "success",
+			"operations": len(operations),
+			"requests":   len(groups[0].requests),
+		})
+	}
+
+	if batchDryRun {
+		batchReqs := map[string]*sheets.BatchUpdateSpreadsheetRequest{}
+		for _, group := range groups {
+			batchReqs[group.spreadsheetID] = &sheets.BatchUpdateSpreadsheetRequest{Requests: group.requests}
+		}
+		return helpers.PrintJSON(batchReqs)
+	}
+
+	progress := helpers.NewProgress("batch")
+	defer progress.Stop()
+	var progressMu sync.Mutex
+	sent := 0
+
+	results := make([]map[string]interface{}, len(groups))
+	helpers.RunParallel(batchConcurrency, len(groups), func(i int) {
+		group := groups[i]
+		result := map[string]interface{}{
+			"status":     "success",
+			"id":         group.spreadsheetID,
+			"operations": len(group.operations),
+			"requests":   len(group.requests),
+		}
+		batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+			Requests: group.requests,
+		}
+		if _, err := service.Spreadsheets.BatchUpdate(group.spreadsheetID, batchReq).Do(); err != nil {
+			result["status"] = "error"
+			result["error"] = fmt.Sprintf("unable to run batch: %v", err)
+		}
+		results[i] = result
+
+		progressMu.Lock()
+		defer progressMu.Unlock()
+		sent++
+		progress.Update(float64(sent)/float64(len(groups)), fmt.Sprintf("%d of %d spreadsheets updated", sent, len(groups)))
+	})
+	progress.Finish(fmt.Sprintf("%d spreadsheets updated", len(groups)))
+
+	return printParallelResults("spreadsheets", results, "batch updates")
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+
+	"spreadsheet-manager/internal/helpers"
+)
+
+var clearCacheCmd = &cobra.Command{
+	Use:   "clear-cache [spreadsheet-id]",
+	Short: "Drop cached spreadsheet metadata, for one spreadsheet or all of them",
+	Long: `Drop cached spreadsheet metadata (sheet names, IDs and grid sizes), for one
+spreadsheet or all of them.
+
+Metadata is only cached on disk with --cache-ttl (or $SPREADSHEET_MANAGER_CACHE_TTL),
+and changes made by this tool invalidate it automatically. Clear it after
+sheets were added, renamed or resized elsewhere, e.g. in the browser.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runClearCache,
+}
+
+func runClearCache(cmd *cobra.Command, args []string) error {
+	if len(args) == 1 {
+		if err := helpers.ClearSpreadsheetCache(args[0]); err != nil {
+			return err
+		}
+		return helpers.PrintJSON(map[string]string{
+			"status":         "success",
+			"spreadsheet_id": args[0],
+		})
+	}
+
+	if err := helpers.ClearSheetCache(); err != nil {
+		return err
+	}
+	return helpers.PrintJSON(map[string]string{
+		"status": "success",
+		"cache":  helpers.SheetCacheDir(),
+	})
+}
@@ -0,0 +1,692 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+// Shorthand legend positions accepted by --legend
+var legendPositions = map[string]string{
+	"bottom": "BOTTOM_LEGEND",
+	"top":    "TOP_LEGEND",
+	"left":   "LEFT_LEGEND",
+	"right":  "RIGHT_LEGEND",
+	"none":   "NO_LEGEND",
+}
+
+var (
+	addChartType      string
+	addChartDataRange string
+	addChartDomain    string
+	addChartSeries    []string
+	addChartTitle     string
+	addChartXLabel    string
+	addChartYLabel    string
+	addChartLegend    string
+	addChartAnchor    string
+	addChartWidth     int
+	addChartHeight    int
+	addChartHeaders   int
+	addChartNewSheet  bool
+)
+
+var addChartCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-chart <spreadsheet-id> <sheet-name>",
+		Short: "Add an embedded chart built from a data range",
+		Long: `Add an embedded chart built from a data range of the sheet.
+
+By default the first column of --data-range is the domain (X axis) and every
+other column is a series. Use --domain and --series (column letters) to pick
+columns explicitly.
+
+Chart types: LINE, BAR, COLUMN, AREA, SCATTER, COMBO, STEPPED_AREA, PIE.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runAddChart,
+	}
+	cmd.Flags().StringVar(&addChartType, "type", "LINE", "Chart type")
+	cmd.Flags().StringVar(&addChartDataRange, "data-range", "", "Data range including the header row (e.g. A1:D20)")
+	cmd.Flags().StringVar(&addChartDomain, "domain", "", "Domain column letter (default: first column of the range)")
+	cmd.Flags().StringSliceVar(&addChartSeries, "series", nil, "Comma-separated series column letters (default: remaining columns)")
+	cmd.Flags().StringVar(&addChartTitle, "title", "", "Chart title")
+	cmd.Flags().StringVar(&addChartXLabel, "x-label", "", "Horizontal axis title")
+	cmd.Flags().StringVar(&addChartYLabel, "y-label", "", "Vertical axis title")
+	cmd.Flags().StringVar(&addChartLegend, "legend", "bottom", "Legend position: bottom, top, left, right, none")
+	cmd.Flags().StringVar(&addChartAnchor, "anchor", "", "Top-left cell of the chart (default: right of the data)")
+	cmd.Flags().IntVar(&addChartWidth, "width", 0, "Chart width in pixels")
+	cmd.Flags().IntVar(&addChartHeight, "height", 0, "Chart height in pixels")
+	cmd.Flags().IntVar(&addChartHeaders, "headers", 1, "Number of header rows in the data range")
+	cmd.Flags().BoolVar(&addChartNewSheet, "new-sheet", false, "Place the chart on its own new sheet")
+	_ = cmd.MarkFlagRequired("data-range")
+	cmd.MarkFlagsMutuallyExclusive("anchor", "new-sheet")
+	return cmd
+}()
+
+func runAddChart(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	dataRange, err := helpers.ParseGridRange(sheetID, addChartDataRange)
+	if err != nil {
+		return err
+	}
+
+	domain, series, err := chartDataColumns(dataRange, addChartDomain, addChartSeries)
+	if err != nil {
+		return err
+	}
+
+	legend, ok := legendPositions[strings.ToLower(addChartLegend)]
+	if !ok {
+		return fmt.Errorf("invalid legend position: %s", addChartLegend)
+	}
+
+	chartType := strings.ToUpper(addChartType)
+	spec := &sheets.ChartSpec{Title: addChartTitle}
+	if chartType == "PIE" {
+		if len(series) != 1 {
+			return fmt.Errorf("PIE charts take exactly one series, got %d", len(series))
+		}
+		spec.PieChart = &sheets.PieChartSpec{
+			Domain:         domain,
+			Series:         series[0],
+			LegendPosition: legend,
+		}
+	} else {
+		spec.BasicChart = buildBasicChart(chartType, domain, series, legend, int64(addChartHeaders))
+		setAxisTitles(spec.BasicChart, addChartXLabel, addChartYLabel)
+	}
+
+	position := &sheets.EmbeddedObjectPosition{}
+	if addChartNewSheet {
+		position.NewSheet = true
+	} else {
+		anchor := &sheets.GridCoordinate{
+			SheetId:     sheetID,
+			RowIndex:    dataRange.StartRowIndex,
+			ColumnIndex: dataRange.EndColumnIndex + 1,
+		}
+		if addChartAnchor != "" {
+			col, row, err := helpers.A1ToGrid(addChartAnchor)
+			if err != nil {
+				return err
+			}
+			anchor.RowIndex, anchor.ColumnIndex = int64(row), int64(col)
+		}
+		position.OverlayPosition = &sheets.OverlayPosition{
+			AnchorCell:   anchor,
+			WidthPixels:  int64(addChartWidth),
+			HeightPixels: int64(addChartHeight),
+		}
+	}
+
+	req := &sheets.Request{
+		AddChart: &sheets.AddChartRequest{
+			Chart: &sheets.EmbeddedChart{
+				Spec:     spec,
+				Position: position,
+			},
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	resp, err := service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to add chart: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"status": "success",
+		"type":   chartType,
+		"range":  addChartDataRange,
+	}
+	if len(resp.Replies) > 0 && resp.Replies[0].AddChart != nil {
+		result["chart_id"] = resp.Replies[0].AddChart.Chart.ChartId
+	}
+
+	return helpers.PrintJSON(result)
+}
+
+// chartAxes returns the domain and value axis positions of a basic chart.
+// BAR charts are horizontal, so their domain lives on the left axis.
+func chartAxes(chartType string) (domainAxis, valueAxis string) {
+	if chartType == "BAR" {
+		return "LEFT_AXIS", "BOTTOM_AXIS"
+	}
+	return "BOTTOM_AXIS", "LEFT_AXIS"
+}
+
+// buildBasicChart creates a BasicChartSpec with one domain and the given series
+func buildBasicChart(chartType string, domain *sheets.ChartData, series []*sheets.ChartData, legend string, headers int64) *sheets.BasicChartSpec {
+	_, valueAxis := chartAxes(chartType)
+
+	basic := &sheets.BasicChartSpec{
+		ChartType:       chartType,
+		LegendPosition:  legend,
+		HeaderCount:     headers,
+		Domains:         []*sheets.BasicChartDomain{{Domain: domain}},
+		ForceSendFields: []string{"HeaderCount"},
+	}
+	for _, s := range series {
+		basic.Series = append(basic.Series, &sheets.BasicChartSeries{
+			Series:     s,
+			TargetAxis: valueAxis,
+		})
+	}
+
+	return basic
+}
+
+// setAxisTitles sets the horizontal (bottom) and vertical (left) axis titles, leaving empty ones untouched
+func setAxisTitles(basic *sheets.BasicChartSpec, xLabel, yLabel string) {
+	setAxisTitle(basic, "BOTTOM_AXIS", xLabel)
+	setAxisTitle(basic, "LEFT_AXIS", yLabel)
+}
+
+func setAxisTitle(basic *sheets.BasicChartSpec, position, title string) {
+	if title == "" {
+		return
+	}
+	for _, axis := range basic.Axis {
+		if axis.Position == position {
+			axis.Title = title
+			return
+		}
+	}
+	basic.Axis = append(basic.Axis, &sheets.BasicChartAxis{Position: position, Title: title})
+}
+
+// chartDataColumns splits a data range into one domain column and series columns.
+// Columns are sheet letters; empty values default to the first column and the rest.
+func chartDataColumns(dataRange *sheets.GridRange, domainCol string, seriesCols []string) (*sheets.ChartData, []*sheets.ChartData, error) {
+	if dataRange.EndColumnIndex == 0 {
+		return nil, nil, fmt.Errorf("data range must have bounded columns (e.g. A1:D20)")
+	}
+
+	column := func(letters string) (int64, error) {
+		col, err := helpers.ColumnIndex(strings.TrimSpace(letters))
+		if err != nil {
+			return 0, err
+		}
+		if int64(col) < dataRange.StartColumnIndex || int64(col) >= dataRange.EndColumnIndex {
+			return 0, fmt.Errorf("column %s is outside the data range", letters)
+		}
+		return int64(col), nil
+	}
+
+	domainIndex := dataRange.StartColumnIndex
+	if domainCol != "" {
+		var err error
+		domainIndex, err = column(domainCol)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var seriesIndexes []int64
+	if len(seriesCols) > 0 {
+		for _, letters := range seriesCols {
+			col, err := column(letters)
+			if err != nil {
+				return nil, nil, err
+			}
+			seriesIndexes = append(seriesIndexes, col)
+		}
+	} else {
+		for col := dataRange.StartColumnIndex; col < dataRange.EndColumnIndex; col++ {
+			if col != domainIndex {
+				seriesIndexes = append(seriesIndexes, col)
+			}
+		}
+	}
+	if len(seriesIndexes) == 0 {
+		return nil, nil, fmt.Errorf("data range has no series columns")
+	}
+
+	var series []*sheets.ChartData
+	for _, col := range seriesIndexes {
+		series = append(series, chartColumnData(dataRange, col))
+	}
+
+	return chartColumnData(dataRange, domainIndex), series, nil
+}
+
+func chartColumnData(dataRange *sheets.GridRange, col int64) *sheets.ChartData {
+	return &sheets.ChartData{
+		SourceRange: &sheets.ChartSourceRange{
+			Sources: []*sheets.GridRange{{
+				SheetId:          dataRange.SheetId,
+				StartRowIndex:    dataRange.StartRowIndex,
+				EndRowIndex:      dataRange.EndRowIndex,
+				StartColumnIndex: col,
+				EndColumnIndex:   col + 1,
+			}},
+		},
+	}
+}
+
+var listChartsCmd = &cobra.Command{
+	Use:   "list-charts <spreadsheet-id> [sheet-name]",
+	Short: "List embedded charts",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  runListCharts,
+}
+
+func runListCharts(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetID).
+		Fields("sheets(properties(sheetId,title,index),charts(chartId,position,spec(title,basicChart.chartType,pieChart)))").Do()
+	if err != nil {
+		return fmt.Errorf("unable to get spreadsheet: %w", err)
+	}
+
+	var onlySheetID *int64
+	if len(args) > 1 {
+		props, err := helpers.FindSheet(spreadsheet, args[1])
+		if err != nil {
+			return err
+		}
+		onlySheetID = &props.SheetId
+	}
+
+	charts := []map[string]interface{}{}
+	for _, sheet := range spreadsheet.Sheets {
+		if onlySheetID != nil && sheet.Properties.SheetId != *onlySheetID {
+			continue
+		}
+		for _, chart := range sheet.Charts {
+			info := map[string]interface{}{
+				"chart_id":   chart.ChartId,
+				"sheet_name": sheet.Properties.Title,
+				"type":       chartTypeName(chart.Spec),
+				"title":      chart.Spec.Title,
+			}
+			if chart.Position != nil && chart.Position.OverlayPosition != nil && chart.Position.OverlayPosition.AnchorCell != nil {
+				anchor := chart.Position.OverlayPosition.AnchorCell
+				info["anchor"] = helpers.GridToA1(int(anchor.ColumnIndex), int(anchor.RowIndex))
+			} else {
+				info["own_sheet"] = true
+			}
+			charts = append(charts, info)
+		}
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status": "success",
+		"charts": charts,
+	})
+}
+
+func chartTypeName(spec *sheets.ChartSpec) string {
+	switch {
+	case spec == nil:
+		return ""
+	case spec.BasicChart != nil:
+		return spec.BasicChart.ChartType
+	case spec.PieChart != nil:
+		return "PIE"
+	}
+	return "OTHER"
+}
+
+var (
+	updateChartTitle     string
+	updateChartDataRange string
+	updateChartDomain    string
+	updateChartSeries    []string
+	updateChartXLabel    string
+	updateChartYLabel    string
+	updateChartLegend    string
+)
+
+var updateChartCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-chart <spreadsheet-id> <chart-id>",
+		Short: "Change the title, data range or labels of a chart",
+		Long: `Change the title, data range or labels of a chart. Unset options keep their current value.
+
+--data-range is sheet-qualified (e.g. Sheet1!A1:D40) and is split into domain
+and series like add-chart.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runUpdateChart,
+	}
+	cmd.Flags().StringVar(&updateChartTitle, "title", "", "New chart title")
+	cmd.Flags().StringVar(&updateChartDataRange, "data-range", "", "New sheet-qualified data range")
+	cmd.Flags().StringVar(&updateChartDomain, "domain", "", "Domain column letter used with --data-range")
+	cmd.Flags().StringSliceVar(&updateChartSeries, "series", nil, "Series column letters used with --data-range")
+	cmd.Flags().StringVar(&updateChartXLabel, "x-label", "", "Horizontal axis title")
+	cmd.Flags().StringVar(&updateChartYLabel, "y-label", "", "Vertical axis title")
+	cmd.Flags().StringVar(&updateChartLegend, "legend", "", "Legend position: bottom, top, left, right, none")
+	return cmd
+}()
+
+func runUpdateChart(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	chartID, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid chart ID: %s", args[1])
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetID).
+		Fields("sheets(properties(sheetId,title,index),charts(chartId,spec))").Do()
+	if err != nil {
+		return fmt.Errorf("unable to get spreadsheet: %w", err)
+	}
+
+	var spec *sheets.ChartSpec
+	for _, sheet := range spreadsheet.Sheets {
+		for _, chart := range sheet.Charts {
+			if chart.ChartId == chartID {
+				spec = chart.Spec
+			}
+		}
+	}
+	if spec == nil {
+		return fmt.Errorf("chart %d not found", chartID)
+	}
+	if spec.BasicChart == nil && spec.PieChart == nil {
+		return fmt.Errorf("chart %d is not a basic or pie chart and cannot be updated", chartID)
+	}
+
+	if cmd.Flags().Changed("title") {
+		spec.Title = updateChartTitle
+	}
+
+	if updateChartDataRange != "" {
+		dataRange, err := resolveSheetGridRange(spreadsheet, updateChartDataRange)
+		if err != nil {
+			return err
+		}
+		domain, series, err := chartDataColumns(dataRange, updateChartDomain, updateChartSeries)
+		if err != nil {
+			return err
+		}
+		if spec.PieChart != nil {
+			if len(series) != 1 {
+				return fmt.Errorf("PIE charts take exactly one series, got %d", len(series))
+			}
+			spec.PieChart.Domain, spec.PieChart.Series = domain, series[0]
+		} else {
+			rebuilt := buildBasicChart(spec.BasicChart.ChartType, domain, series, spec.BasicChart.LegendPosition, spec.BasicChart.HeaderCount)
+			spec.BasicChart.Domains, spec.BasicChart.Series = rebuilt.Domains, rebuilt.Series
+		}
+	}
+
+	if updateChartLegend != "" {
+		legend, ok := legendPositions[strings.ToLower(updateChartLegend)]
+		if !ok {
+			return fmt.Errorf("invalid legend position: %s", updateChartLegend)
+		}
+		if spec.PieChart != nil {
+			spec.PieChart.LegendPosition = legend
+		} else {
+			spec.BasicChart.LegendPosition = legend
+		}
+	}
+
+	if spec.BasicChart != nil {
+		setAxisTitles(spec.BasicChart, updateChartXLabel, updateChartYLabel)
+	}
+
+	req := &sheets.Request{
+		UpdateChartSpec: &sheets.UpdateChartSpecRequest{
+			ChartId: chartID,
+			Spec:    spec,
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to update chart: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":   "success",
+		"chart_id": chartID,
+		"title":    spec.Title,
+	})
+}
+
+var deleteChartCmd = &cobra.Command{
+	Use:   "delete-chart <spreadsheet-id> <chart-id>",
+	Short: "Delete an embedded chart",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runDeleteChart,
+}
+
+func runDeleteChart(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	chartID, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid chart ID: %s", args[1])
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.Request{
+		DeleteEmbeddedObject: &sheets.DeleteEmbeddedObjectRequest{
+			ObjectId: chartID,
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to delete chart: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":   "success",
+		"chart_id": chartID,
+	})
+}
+
+var (
+	addSparklinesSource        string
+	addSparklinesType          string
+	addSparklinesFirstRow      int
+	addSparklinesLastRow       int
+	addSparklinesColor         string
+	addSparklinesNegativeColor string
+	addSparklinesMin           string
+	addSparklinesMax           string
+	addSparklinesLineWidth     float64
+)
+
+var addSparklinesCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-sparklines <spreadsheet-id> <sheet-name> <target-column>",
+		Short: "Write SPARKLINE formulas down a column, one per data row",
+		Long: `Write =SPARKLINE(...) formulas down a column, one per data row.
+
+Each row's sparkline plots the --source columns of that row, e.g. --source B:M
+writes =SPARKLINE(B2:M2, {...}) in row 2. Without --last-row, the last populated
+row of the sheet is detected automatically.`,
+		Args: cobra.ExactArgs(3),
+		RunE: runAddSparklines,
+	}
+	cmd.Flags().StringVar(&addSparklinesSource, "source", "", "Source columns plotted on each row (e.g. B:M)")
+	cmd.Flags().StringVar(&addSparklinesType, "type", "line", "Sparkline type: line, column, bar, winloss")
+	cmd.Flags().IntVar(&addSparklinesFirstRow, "first-row", 2, "First 1-based row to write")
+	cmd.Flags().IntVar(&addSparklinesLastRow, "last-row", 0, "Last 1-based row to write (default: last populated row)")
+	cmd.Flags().StringVar(&addSparklinesColor, "color", "", "Line/bar color (name or hex)")
+	cmd.Flags().StringVar(&addSparklinesNegativeColor, "negative-color", "", "Color of negative columns (column, winloss)")
+	cmd.Flags().StringVar(&addSparklinesMin, "min", "", "Minimum value of the scale (not for bar)")
+	cmd.Flags().StringVar(&addSparklinesMax, "max", "", "Maximum value of the scale")
+	cmd.Flags().Float64Var(&addSparklinesLineWidth, "line-width", 0, "Line width (line)")
+	_ = cmd.MarkFlagRequired("source")
+	return cmd
+}()
+
+func runAddSparklines(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	targetColumn := strings.ToUpper(args[2])
+
+	if _, err := helpers.ColumnIndex(targetColumn); err != nil {
+		return err
+	}
+
+	startCol, endCol, ok := strings.Cut(strings.ToUpper(addSparklinesSource), ":")
+	if !ok {
+		return fmt.Errorf("invalid --source '%s': expected a column span like B:M", addSparklinesSource)
+	}
+	// Each row gets its own span, so the halves must be bare columns (B2:M2 would become B22:M22)
+	for _, column := range []string{startCol, endCol} {
+		if _, err := helpers.ColumnIndex(column); err != nil {
+			return fmt.Errorf("invalid --source '%s': expected a column span like B:M", addSparklinesSource)
+		}
+	}
+
+	options, err := sparklineOptions()
+	if err != nil {
+		return err
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetTitle, err := helpers.ResolveSheetTitle(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	lastRow := addSparklinesLastRow
+	if lastRow == 0 {
+		resp, err := service.Spreadsheets.Values.Get(spreadsheetID, helpers.SheetRange(sheetTitle, "")).Do()
+		if err != nil {
+			return fmt.Errorf("unable to detect last row: %w", err)
+		}
+		lastRow = len(resp.Values)
+	}
+	if lastRow < addSparklinesFirstRow {
+		return fmt.Errorf("nothing to write: last row %d is above first row %d", lastRow, addSparklinesFirstRow)
+	}
+
+	var values [][]interface{}
+	for row := addSparklinesFirstRow; row <= lastRow; row++ {
+		formula := fmt.Sprintf("=SPARKLINE(%s%d:%s%d%s)", startCol, row, endCol, row, options)
+		values = append(values, []interface{}{formula})
+	}
+
+	rangeA1 := fmt.Sprintf("%s%d:%s%d", targetColumn, addSparklinesFirstRow, targetColumn, lastRow)
+	valueRange := &sheets.ValueRange{
+		Values: values,
+	}
+
+	_, err = service.Spreadsheets.Values.Update(
+		spreadsheetID,
+		helpers.SheetRange(sheetTitle, rangeA1),
+		valueRange,
+	).ValueInputOption(ValueInputModeFormula).Do()
+	if err != nil {
+		return fmt.Errorf("unable to write sparklines: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status": "success",
+		"range":  rangeA1,
+		"rows":   len(values),
+	})
+}
+
+// sparklineOptions renders the SPARKLINE options array (e.g. `, {"charttype","column";"color","red"}`)
+func sparklineOptions() (string, error) {
+	chartType := strings.ToLower(addSparklinesType)
+	switch chartType {
+	case "line", "column", "bar", "winloss":
+	default:
+		return "", fmt.Errorf("invalid sparkline type: %s", addSparklinesType)
+	}
+	// A bar sparkline only takes a maximum; SPARKLINE ignores ymin for it
+	if chartType == "bar" && addSparklinesMin != "" {
+		return "", fmt.Errorf("--min is not supported for bar sparklines")
+	}
+
+	options := [][2]string{{"charttype", chartType}}
+	if addSparklinesColor != "" {
+		name := "color"
+		if chartType == "bar" {
+			name = "color1"
+		}
+		options = append(options, [2]string{name, addSparklinesColor})
+	}
+	if addSparklinesNegativeColor != "" {
+		options = append(options, [2]string{"negcolor", addSparklinesNegativeColor})
+	}
+	if addSparklinesMin != "" {
+		options = append(options, [2]string{"ymin", addSparklinesMin})
+	}
+	if addSparklinesMax != "" {
+		name := "ymax"
+		if chartType == "bar" {
+			name = "max"
+		}
+		options = append(options, [2]string{name, addSparklinesMax})
+	}
+	if addSparklinesLineWidth > 0 {
+		options = append(options, [2]string{"linewidth", strconv.FormatFloat(addSparklinesLineWidth, 'f', -1, 64)})
+	}
+
+	parts := make([]string, len(options))
+	for i, option := range options {
+		value := option[1]
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			value = strconv.Quote(value)
+		}
+		parts[i] = fmt.Sprintf("%q,%s", option[0], value)
+	}
+
+	return ", {" + strings.Join(parts, ";") + "}", nil
+}
@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"spreadsheet-manager/internal/helpers"
+)
+
+// importCheckpoint records how far a chunked import got, so --resume can continue after the
+// last chunk the API acknowledged. Size and ModTime detect a file changed in between
+type importCheckpoint struct {
+	SpreadsheetID string    `json:"spreadsheet_id"`
+	Sheet         string    `json:"sheet"`
+	File          string    `json:"file"`
+	Size          int64     `json:"size"`
+	ModTime       time.Time `json:"mod_time"`
+	Mode          string    `json:"mode"`
+	Start         string    `json:"start"`
+	Rows          int       `json:"rows"`
+	Offset        int       `json:"offset"`
+	Types         []string  `json:"types,omitempty"`
+	Updated       time.Time `json:"updated"`
+}
+
+// importCheckpointDir is where checkpoints of unfinished imports are kept, an empty string
+// when the system has no cache directory
+func importCheckpointDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, helpers.CacheDirName, "imports")
+}
+
+// newImportCheckpoint describes the import of a local file into a sheet, or returns nil for
+// stdin, which cannot be read again
+func newImportCheckpoint(spreadsheetID, sheetTitle, csvPath string) *importCheckpoint {
+	if csvPath == "-" {
+		return nil
+	}
+	path, err := filepath.Abs(csvPath)
+	if err != nil {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return nil
+	}
+	return &importCheckpoint{
+		SpreadsheetID: spreadsheetID,
+		Sheet:         sheetTitle,
+		File:          path,
+		Size:          info.Size(),
+		ModTime:       info.ModTime(),
+		Mode:          importCSVMode,
+		Start:         importCSVStartCell,
+	}
+}
+
+// path names the checkpoint after the spreadsheet, sheet and file it belongs to
+func (c *importCheckpoint) path() string {
+	dir := importCheckpointDir()
+	if dir == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(c.SpreadsheetID + "\x00" + c.Sheet + "\x00" + c.File))
+	return filepath.Join(dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// load reads the checkpoint of a previous run of the same import and checks it can be resumed
+func (c *importCheckpoint) load() (*importCheckpoint, error) {
+	path := c.path()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) || path == "" {
+		return nil, fmt.Errorf("no checkpoint to resume for %s into '%s' (the import finished or never started)", c.File, c.Sheet)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read checkpoint: %w", err)
+	}
+
+	var saved importCheckpoint
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint %s: %w", path, err)
+	}
+	if saved.Size != c.Size || !saved.ModTime.Equal(c.ModTime) {
+		return nil, fmt.Errorf("%s changed since the checkpoint, resume with --resume-from instead", c.File)
+	}
+	if saved.Mode != c.Mode || saved.Start != c.Start {
+		return nil, fmt.Errorf("the checkpoint was written with --mode %s --start %s", saved.Mode, saved.Start)
+	}
+	return &saved, nil
+}
+
+// save writes the checkpoint through a temporary file, so a crash never leaves a torn one
+func (c *importCheckpoint) save() error {
+	path := c.path()
+	if path == "" {
+		return fmt.Errorf("no cache directory")
+	}
+
+	c.Updated = time.Now()
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), helpers.CacheDirMode); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path+".tmp", data, helpers.CacheFileMode); err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+func (c *importCheckpoint) remove() {
+	if path := c.path(); path != "" {
+		_ = os.Remove(path)
+	}
+}
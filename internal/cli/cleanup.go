@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+var trimWhitespaceCmd = &cobra.Command{
+	Use:   "trim-whitespace <spreadsheet-id> <sheet-name> [range]",
+	Short: "Trim leading/trailing whitespace in a range (whole sheet by default)",
+	Args:  cobra.RangeArgs(2, 3),
+	RunE:  runTrimWhitespace,
+}
+
+func runTrimWhitespace(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	gridRange := &sheets.GridRange{SheetId: sheetID, ForceSendFields: []string{"SheetId"}}
+	rangeA1 := ""
+	if len(args) == 3 {
+		rangeA1 = args[2]
+		gridRange, err = helpers.ParseGridRange(sheetID, rangeA1)
+		if err != nil {
+			return err
+		}
+	}
+
+	req := &sheets.Request{
+		TrimWhitespace: &sheets.TrimWhitespaceRequest{
+			Range: gridRange,
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	resp, err := service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to trim whitespace: %w", err)
+	}
+
+	var changed int64
+	if len(resp.Replies) > 0 && resp.Replies[0].TrimWhitespace != nil {
+		changed = resp.Replies[0].TrimWhitespace.CellsChangedCount
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":        "success",
+		"range":         rangeA1,
+		"cells_changed": changed,
+	})
+}
+
+var textToColumnsDelimiter string
+
+var textToColumnsCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "text-to-columns <spreadsheet-id> <sheet-name> <column-range>",
+		Short: "Split a single column of text into several columns",
+		Long: `Split a single column of text (e.g. "A" or "A2:A100") into several columns.
+
+The delimiter can be comma, semicolon, period, space, auto, or any other string
+which is used as a custom delimiter.`,
+		Args: cobra.ExactArgs(3),
+		RunE: runTextToColumns,
+	}
+	cmd.Flags().StringVar(&textToColumnsDelimiter, "delimiter", "comma", "Delimiter: comma, semicolon, period, space, auto, or a custom string")
+	return cmd
+}()
+
+func runTextToColumns(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	rangeA1 := args[2]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	if !strings.Contains(rangeA1, ":") {
+		rangeA1 = rangeA1 + ":" + rangeA1
+	}
+
+	source, err := helpers.ParseGridRange(sheetID, rangeA1)
+	if err != nil {
+		return err
+	}
+	if source.EndColumnIndex-source.StartColumnIndex != 1 {
+		return fmt.Errorf("range '%s' must span exactly one column", args[2])
+	}
+
+	textToColumns := &sheets.TextToColumnsRequest{Source: source}
+	switch strings.ToLower(textToColumnsDelimiter) {
+	case "comma", ",":
+		textToColumns.DelimiterType = "COMMA"
+	case "semicolon", ";":
+		textToColumns.DelimiterType = "SEMICOLON"
+	case "period", ".":
+		textToColumns.DelimiterType = "PERIOD"
+	case "space", " ":
+		textToColumns.DelimiterType = "SPACE"
+	case "auto", "autodetect":
+		textToColumns.DelimiterType = "AUTODETECT"
+	case "":
+		return fmt.Errorf("--delimiter cannot be empty")
+	default:
+		textToColumns.DelimiterType = "CUSTOM"
+		textToColumns.Delimiter = textToColumnsDelimiter
+	}
+
+	req := &sheets.Request{
+		TextToColumns: textToColumns,
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to split text to columns: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":    "success",
+		"range":     args[2],
+		"delimiter": textToColumns.DelimiterType,
+	})
+}
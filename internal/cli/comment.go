@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+// commentFields is the Drive fields mask of a comment and its replies
+const commentFields = "id,content,author(displayName,emailAddress),createdTime,resolved,anchor,quotedFileContent(value),replies(id,content,action,author(displayName,emailAddress),createdTime)"
+
+var addCommentCell string
+
+var addCommentCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-comment <spreadsheet-id> <text>",
+		Short: "Start a comment thread on a spreadsheet, optionally anchored to a cell",
+		Long: `Start a comment thread on a spreadsheet, optionally anchored to a cell.
+
+Unlike notes, comments are threaded and can be replied to and resolved.
+--cell takes a sheet-qualified cell (e.g. "Sheet1!B3"); the reference is stored
+as the quoted content and the sheet ID and cell as a Drive matrix anchor. The Sheets UI
+may list API comments without highlighting the anchored cell.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runAddComment,
+	}
+	cmd.Flags().StringVar(&addCommentCell, "cell", "", "Sheet-qualified cell to anchor the comment to (e.g. Sheet1!B3)")
+	return cmd
+}()
+
+func runAddComment(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	comment := &drive.Comment{Content: args[1]}
+	if addCommentCell != "" {
+		service, err := auth.GetSheetsService(ctx)
+		if err != nil {
+			return err
+		}
+		anchor, err := cellAnchor(service, spreadsheetID, addCommentCell)
+		if err != nil {
+			return err
+		}
+		comment.Anchor = anchor
+		comment.QuotedFileContent = &drive.CommentQuotedFileContent{
+			MimeType: "text/plain",
+			Value:    addCommentCell,
+		}
+	}
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := driveService.Comments.Create(spreadsheetID, comment).Fields(commentFields).Do()
+	if err != nil {
+		return fmt.Errorf("unable to add comment: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":  "success",
+		"comment": commentEntry(result),
+	})
+}
+
+// cellAnchor builds a Drive anchor for one cell of a sheet-qualified reference. The sheet must
+// exist; its ID goes into the anchor so the same cell on different sheets is told apart.
+func cellAnchor(service *sheets.Service, spreadsheetID, ref string) (string, error) {
+	sheetName, cell := helpers.SplitSheetRange(ref)
+	if sheetName == "" {
+		return "", fmt.Errorf("invalid --cell '%s': expected a sheet-qualified cell such as Sheet1!B3", ref)
+	}
+
+	col, row, err := helpers.A1ToGrid(cell)
+	if err != nil || col < 0 || row < 0 {
+		return "", fmt.Errorf("invalid cell reference: %s", cell)
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return "", err
+	}
+
+	anchor, err := json.Marshal(map[string]interface{}{
+		"r": "head",
+		"a": []interface{}{
+			map[string]interface{}{
+				"matrix": map[string]int64{"s": sheetID, "c": int64(col), "r": int64(row), "w": 1, "h": 1},
+			},
+		},
+	})
+	return string(anchor), err
+}
+
+var listCommentsIncludeResolved bool
+
+var listCommentsCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-comments <spreadsheet-id>",
+		Short: "List the comment threads of a spreadsheet with their replies",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runListComments,
+	}
+	cmd.Flags().BoolVar(&listCommentsIncludeResolved, "include-resolved", false, "Include resolved threads")
+	return cmd
+}()
+
+func runListComments(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	result := []map[string]interface{}{}
+	err = driveService.Comments.List(spreadsheetID).
+		Fields("nextPageToken,comments("+commentFields+")").
+		Pages(ctx, func(page *drive.CommentList) error {
+			for _, comment := range page.Comments {
+				if comment.Resolved && !listCommentsIncludeResolved {
+					continue
+				}
+				result = append(result, commentEntry(comment))
+			}
+			return nil
+		})
+	if err != nil {
+		return fmt.Errorf("unable to list comments: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":   "success",
+		"count":    len(result),
+		"comments": result,
+	})
+}
+
+func commentEntry(comment *drive.Comment) map[string]interface{} {
+	entry := map[string]interface{}{
+		"id":       comment.Id,
+		"content":  comment.Content,
+		"created":  comment.CreatedTime,
+		"resolved": comment.Resolved,
+	}
+	if comment.Author != nil {
+		entry["author"] = comment.Author.DisplayName
+	}
+	if comment.QuotedFileContent != nil && comment.QuotedFileContent.Value != "" {
+		entry["quoted"] = comment.QuotedFileContent.Value
+	}
+	if comment.Anchor != "" {
+		entry["anchor"] = comment.Anchor
+	}
+
+	if len(comment.Replies) > 0 {
+		replies := make([]map[string]interface{}, 0, len(comment.Replies))
+		for _, reply := range comment.Replies {
+			replies = append(replies, replyEntry(reply))
+		}
+		entry["replies"] = replies
+	}
+
+	return entry
+}
+
+func replyEntry(reply *drive.Reply) map[string]interface{} {
+	entry := map[string]interface{}{
+		"id":      reply.Id,
+		"content": reply.Content,
+		"created": reply.CreatedTime,
+	}
+	if reply.Author != nil {
+		entry["author"] = reply.Author.DisplayName
+	}
+	if reply.Action != "" {
+		entry["action"] = reply.Action
+	}
+	return entry
+}
+
+var replyCommentCmd = &cobra.Command{
+	Use:   "reply-comment <spreadsheet-id> <comment-id> <text>",
+	Short: "Reply to a comment thread",
+	Args:  cobra.ExactArgs(3),
+	RunE:  runReplyComment,
+}
+
+func runReplyComment(cmd *cobra.Command, args []string) error {
+	return createReply(args[0], args[1], &drive.Reply{Content: args[2]})
+}
+
+var resolveCommentMessage string
+
+var resolveCommentCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resolve-comment <spreadsheet-id> <comment-id>",
+		Short: "Resolve a comment thread",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runResolveComment,
+	}
+	cmd.Flags().StringVar(&resolveCommentMessage, "message", "", "Reply posted along with the resolution")
+	return cmd
+}()
+
+func runResolveComment(cmd *cobra.Command, args []string) error {
+	return createReply(args[0], args[1], &drive.Reply{
+		Content: resolveCommentMessage,
+		Action:  "resolve",
+	})
+}
+
+// createReply posts a reply to a comment thread; an action ("resolve", "reopen") changes the thread state
+func createReply(spreadsheetID, commentID string, reply *drive.Reply) error {
+	ctx := context.Background()
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	result, err := driveService.Replies.Create(spreadsheetID, commentID, reply).
+		Fields("id,content,action,author(displayName),createdTime").Do()
+	if err != nil {
+		return fmt.Errorf("unable to reply to comment %s: %w", commentID, err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":     "success",
+		"comment_id": commentID,
+		"reply":      replyEntry(result),
+	})
+}
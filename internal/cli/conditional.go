@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+// Shorthand condition names accepted by --condition in addition to raw API condition types
+var conditionAliases = map[string]string{
+	">":            "NUMBER_GREATER",
+	">=":           "NUMBER_GREATER_THAN_EQ",
+	"<":            "NUMBER_LESS",
+	"<=":           "NUMBER_LESS_THAN_EQ",
+	"==":           "NUMBER_EQ",
+	"!=":           "NUMBER_NOT_EQ",
+	"between":      "NUMBER_BETWEEN",
+	"not-between":  "NUMBER_NOT_BETWEEN",
+	"contains":     "TEXT_CONTAINS",
+	"not-contains": "TEXT_NOT_CONTAINS",
+	"starts-with":  "TEXT_STARTS_WITH",
+	"ends-with":    "TEXT_ENDS_WITH",
+	"text-eq":      "TEXT_EQ",
+	"empty":        "BLANK",
+	"not-empty":    "NOT_BLANK",
+	"formula":      "CUSTOM_FORMULA",
+}
+
+var (
+	conditionalFormatCondition string
+	conditionalFormatValues    []string
+	conditionalFormatBgColor   string
+	conditionalFormatFontColor string
+	conditionalFormatBold      bool
+	conditionalFormatItalic    bool
+	conditionalFormatRule      string
+	conditionalFormatIndex     int
+)
+
+var conditionalFormatCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "conditional-format <spreadsheet-id> <sheet-name> <range>",
+		Short: "Add a conditional formatting rule to a range",
+		Long: `Add a conditional formatting rule to a range.
+
+Build a boolean rule from flags, e.g. --condition ">" --value 100 --bg-color "#f4cccc",
+or pass a full ConditionalFormatRule as JSON with --rule (its ranges default to <range>).
+
+Condition shorthands: >, >=, <, <=, ==, !=, between, not-between, contains,
+not-contains, starts-with, ends-with, text-eq, empty, not-empty, formula.
+Any API condition type (e.g. DATE_BEFORE) is also accepted.`,
+		Args: cobra.ExactArgs(3),
+		RunE: runConditionalFormat,
+	}
+	cmd.Flags().StringVar(&conditionalFormatCondition, "condition", "", "Condition type or shorthand")
+	cmd.Flags().StringArrayVar(&conditionalFormatValues, "value", nil, "Condition value (repeat for between)")
+	cmd.Flags().StringVar(&conditionalFormatBgColor, "bg-color", "", "Background color when matched (hex)")
+	cmd.Flags().StringVar(&conditionalFormatFontColor, "font-color", "", "Font color when matched (hex)")
+	cmd.Flags().BoolVar(&conditionalFormatBold, "bold", false, "Bold text when matched")
+	cmd.Flags().BoolVar(&conditionalFormatItalic, "italic", false, "Italic text when matched")
+	cmd.Flags().StringVar(&conditionalFormatRule, "rule", "", "Full ConditionalFormatRule as JSON")
+	cmd.Flags().IntVar(&conditionalFormatIndex, "index", 0, "Rule priority (0 is evaluated first)")
+	cmd.MarkFlagsMutuallyExclusive("rule", "condition")
+	return cmd
+}()
+
+func runConditionalFormat(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	rangeA1 := args[2]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	gridRange, err := helpers.ParseGridRange(sheetID, rangeA1)
+	if err != nil {
+		return err
+	}
+
+	rule, err := buildConditionalFormatRule(gridRange)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.Request{
+		AddConditionalFormatRule: &sheets.AddConditionalFormatRuleRequest{
+			Rule:            rule,
+			Index:           int64(conditionalFormatIndex),
+			ForceSendFields: []string{"Index"},
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to add conditional format rule: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status": "success",
+		"range":  rangeA1,
+		"index":  conditionalFormatIndex,
+	})
+}
+
+func buildConditionalFormatRule(gridRange *sheets.GridRange) (*sheets.ConditionalFormatRule, error) {
+	if conditionalFormatRule != "" {
+		rule := &sheets.ConditionalFormatRule{}
+		if err := json.Unmarshal([]byte(conditionalFormatRule), rule); err != nil {
+			return nil, fmt.Errorf("invalid JSON rule: %w", err)
+		}
+		if len(rule.Ranges) == 0 {
+			rule.Ranges = []*sheets.GridRange{gridRange}
+		}
+		return rule, nil
+	}
+
+	if conditionalFormatCondition == "" {
+		return nil, fmt.Errorf("either --condition or --rule is required")
+	}
+
+	conditionType := conditionalFormatCondition
+	if alias, ok := conditionAliases[strings.ToLower(conditionType)]; ok {
+		conditionType = alias
+	}
+
+	condition := &sheets.BooleanCondition{Type: strings.ToUpper(conditionType)}
+	for _, value := range conditionalFormatValues {
+		condition.Values = append(condition.Values, &sheets.ConditionValue{UserEnteredValue: value})
+	}
+
+	format := &sheets.CellFormat{}
+	if conditionalFormatBgColor != "" {
+		format.BackgroundColor = helpers.ParseColor(conditionalFormatBgColor)
+	}
+	if conditionalFormatFontColor != "" || conditionalFormatBold || conditionalFormatItalic {
+		format.TextFormat = &sheets.TextFormat{
+			ForegroundColor: helpers.ParseColor(conditionalFormatFontColor),
+			Bold:            conditionalFormatBold,
+			Italic:          conditionalFormatItalic,
+		}
+	}
+
+	return &sheets.ConditionalFormatRule{
+		Ranges: []*sheets.GridRange{gridRange},
+		BooleanRule: &sheets.BooleanRule{
+			Condition: condition,
+			Format:    format,
+		},
+	}, nil
+}
@@ -1,8 +1,27 @@
 package cli
 
 const (
-	DefaultStartCell       = "A1"
-	GoogleSheetsURLPattern = "https://docs.google.com/spreadsheets/d/%s/edit"
-	ValueInputModeFormula  = "USER_ENTERED"
-	ValueInputModeRaw      = "RAW"
+	DateRenderFormatted              = "FORMATTED_STRING"
+	DateRenderSerial                 = "SERIAL_NUMBER"
+	DefaultConcurrency               = 4
+	DefaultImportChunkSize           = 10000
+	DefaultStartCell                 = "A1"
+	DriveImageURLPattern             = "https://lh3.googleusercontent.com/d/%s"
+	GoogleSheetsChartImageURLPattern = "https://docs.google.com/spreadsheets/d/%s/embed/oimg?id=%d&oid=%d&format=image"
+	GoogleSheetsExportURLPattern     = "https://docs.google.com/spreadsheets/d/%s/export"
+	GoogleSheetsPublishURLPattern    = "https://docs.google.com/spreadsheets/d/%s/pubhtml"
+	GoogleSheetsURLPattern           = "https://docs.google.com/spreadsheets/d/%s/edit"
+	InsertDataOptionRows             = "INSERT_ROWS"
+	MajorDimensionColumns            = "COLUMNS"
+	MajorDimensionRows               = "ROWS"
+	MergeTypeAll                     = "MERGE_ALL"
+	MimeTypeGoogleSheets             = "application/vnd.google-apps.spreadsheet"
+	MimeTypeODS                      = "application/x-vnd.oasis.opendocument.spreadsheet"
+	MimeTypeXLSX                     = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	ValueInputModeFormula            = "USER_ENTERED"
+	ValueInputModeRaw                = "RAW"
+	ValueRenderFormatted             = "FORMATTED_VALUE"
+	ValueRenderFormula               = "FORMULA"
+	ValueRenderUnformatted           = "UNFORMATTED_VALUE"
+	WrapStrategyOverflow             = "OVERFLOW_CELL"
 )
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"google.golang.org/api/drive/v3"
-	"google.golang.org/api/option"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/sheets/v4"
 
 	"spreadsheet-manager/internal/auth"
@@ -43,22 +45,12 @@ func runCreate(cmd *cobra.Command, args []string) error {
 }
 
 func createFromTemplate(ctx context.Context, title, templateID, folderID string) error {
-	client, err := auth.GetClient(ctx)
+	driveService, err := auth.GetDriveService(ctx)
 	if err != nil {
 		return err
 	}
 
-	driveService, err := drive.NewService(ctx, option.WithHTTPClient(client))
-	if err != nil {
-		return fmt.Errorf("unable to create Drive service: %w", err)
-	}
-
-	file := &drive.File{Name: title}
-	if folderID != "" {
-		file.Parents = []string{folderID}
-	}
-
-	result, err := driveService.Files.Copy(templateID, file).Do()
+	result, err := copySpreadsheet(driveService, templateID, title, folderID)
 	if err != nil {
 		return fmt.Errorf("unable to copy template: %w", err)
 	}
@@ -69,6 +61,16 @@ func createFromTemplate(ctx context.Context, title, templateID, folderID string)
 	})
 }
 
+// copySpreadsheet copies a spreadsheet with Drive Files.Copy, into folderID when not empty
+func copySpreadsheet(driveService *drive.Service, spreadsheetID, title, folderID string) (*drive.File, error) {
+	file := &drive.File{Name: title}
+	if folderID != "" {
+		file.Parents = []string{folderID}
+	}
+
+	return driveService.Files.Copy(spreadsheetID, file).Fields("id,name").Do()
+}
+
 func createNew(ctx context.Context, title, folderID string) error {
 	service, err := auth.GetSheetsService(ctx)
 	if err != nil {
@@ -81,7 +83,7 @@ func createNew(ctx context.Context, title, folderID string) error {
 		},
 	}
 
-	result, err := service.Spreadsheets.Create(spreadsheet).Do()
+	result, err := service.Spreadsheets.Create(spreadsheet).Fields("spreadsheetId").Do()
 	if err != nil {
 		return fmt.Errorf("unable to create spreadsheet: %w", err)
 	}
@@ -98,17 +100,96 @@ func createNew(ctx context.Context, title, folderID string) error {
 	})
 }
 
+// moveToFolder puts a file in folderID and takes it out of its current folders
 func moveToFolder(ctx context.Context, spreadsheetID, folderID string) error {
-	client, err := auth.GetClient(ctx)
+	driveService, err := auth.GetDriveService(ctx)
 	if err != nil {
 		return err
 	}
 
-	driveService, err := drive.NewService(ctx, option.WithHTTPClient(client))
+	file, err := driveService.Files.Get(spreadsheetID).Fields("parents").Do()
 	if err != nil {
-		return fmt.Errorf("unable to create Drive service: %w", err)
+		return err
 	}
 
-	_, err = driveService.Files.Update(spreadsheetID, &drive.File{}).AddParents(folderID).Do()
+	inFolder := false
+	var others []string
+	for _, parent := range file.Parents {
+		if parent == folderID {
+			inFolder = true
+		} else {
+			others = append(others, parent)
+		}
+	}
+	if inFolder && len(others) == 0 {
+		return nil
+	}
+
+	call := driveService.Files.Update(spreadsheetID, &drive.File{})
+	if !inFolder {
+		call.AddParents(folderID)
+	}
+	if len(others) > 0 {
+		call.RemoveParents(strings.Join(others, ","))
+	}
+	_, err = call.Do()
 	return err
 }
+
+var (
+	importXLSXName     string
+	importXLSXFolderID string
+)
+
+var importXLSXCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-xlsx <file.xlsx>",
+		Short: "Upload an Excel file and convert it to a new spreadsheet",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runImportXLSX,
+	}
+	cmd.Flags().StringVar(&importXLSXName, "name", "", "Spreadsheet title (default: file name without extension)")
+	cmd.Flags().StringVar(&importXLSXFolderID, "folder", "", "Folder ID to create spreadsheet in")
+	return cmd
+}()
+
+func runImportXLSX(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	path := args[0]
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("unable to open file: %w", err)
+	}
+	defer file.Close()
+
+	name := importXLSXName
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	metadata := &drive.File{
+		Name:     name,
+		MimeType: MimeTypeGoogleSheets,
+	}
+	if importXLSXFolderID != "" {
+		metadata.Parents = []string{importXLSXFolderID}
+	}
+
+	result, err := driveService.Files.Create(metadata).
+		Media(file, googleapi.ContentType(MimeTypeXLSX)).
+		Fields("id").Do()
+	if err != nil {
+		return fmt.Errorf("unable to import file: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]string{
+		"id":  result.Id,
+		"url": fmt.Sprintf(GoogleSheetsURLPattern, result.Id),
+	})
+}
@@ -1,142 +1,1456 @@
 package cli
 
 import (
+	"bufio"
 	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
 	"google.golang.org/api/sheets/v4"
 
 	"spreadsheet-manager/internal/auth"
 	"spreadsheet-manager/internal/helpers"
 )
 
-var importCSVStartCell string
+var (
+	importCSVStartCell      string
+	importCSVMajorDimension string
+	importCSVDelimiter      string
+	importCSVLazyQuotes     bool
+	importCSVComment        string
+	importCSVEncoding       string
+	importCSVSkipHeader     bool
+	importCSVSelect         []string
+	importCSVColumns        []string
+	importCSVTypes          []string
+	importCSVChunkSize      int
+	importCSVResumeFrom     int
+	importCSVResume         bool
+	importCSVMode           string
+	importCSVKeyColumn      string
+	importCSVCreateSheet    bool
+	importCSVNewSpreadsheet string
+	importCSVConcurrency    int
+)
 
 var importCSVCmd = func() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "import-csv <spreadsheet-id> <sheet-name> <csv-path>",
+		Use:   "import-csv <spreadsheet-id> <sheet-name> <csv-path|->",
 		Short: "Import CSV data into sheet",
-		Args:  cobra.ExactArgs(3),
-		RunE:  runImportCSV,
+		Long: `Import CSV data into sheet.
+
+The delimiter defaults to a tab for .tsv files and a comma otherwise. Use
+--delimiter ';' for European CSVs or --delimiter '\t' (or tab) for TSV.
+A path of "-" reads the CSV from stdin.
+
+Input is transcoded to UTF-8 from --encoding (e.g. windows-1252, utf-16). A
+UTF-8 or UTF-16 byte order mark always wins over --encoding and is dropped.
+
+Source columns are referenced by header name or 1-based position. --select
+keeps and reorders source columns; --columns=name:B,email:D writes each source
+column to a sheet column, leaving the other sheet columns untouched.
+--skip-header uses the first record for names but does not import it.
+
+--types=auto infers number, date (ISO 8601), bool or text per column; explicit
+types are given as name:TYPE (e.g. amount:number,id:text) and the remaining
+columns are inferred. Typed values are written together with a matching
+number format in a single batch update. Cells that do not parse as their
+column type (such as the header) are written as text.
+
+The file is streamed and uploaded in chunks of --chunk-size rows, with progress
+on stderr. If a chunk fails, the error tells how many rows were imported;
+rerun with --resume, or --resume-from N to skip them and continue at the
+same offset.
+With --types, inference only looks at the first chunk.
+
+Chunked imports of a file also keep a checkpoint (rows acknowledged and
+where the next chunk goes) in the user cache directory. --resume reads it and
+continues exactly where the failed run stopped, with the same target rows and
+column types; it refuses a file that changed since. The checkpoint is
+deleted once the import completes.
+
+--mode selects where rows go: overwrite (default) writes at --start; replace
+clears the sheet values first; append writes below the last populated row;
+upsert updates the rows whose --key-column matches and appends the others
+(full rows from column A, so --start, --columns and --types do not apply).
+
+--create-sheet adds the sheet when no sheet has that title. With
+--new-spreadsheet TITLE the spreadsheet ID argument is omitted: a spreadsheet
+is created with a single sheet named <sheet-name>, the file is imported
+into it and the new ID and URL are printed.
+
+With only two arguments, <spreadsheet-id> <dir|glob>, every matching file
+(*.csv for a directory) is imported into a sheet named after the file, missing
+sheets are created, and up to --concurrency files are uploaded at a time.
+Files whose names give the same sheet are rejected. A failed file is
+resumed on its own, with the single-file form the error prints.`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: runImportCSV,
 	}
 	cmd.Flags().StringVar(&importCSVStartCell, "start", DefaultStartCell, "Starting cell")
+	cmd.Flags().StringVar(&importCSVMajorDimension, "major-dimension", MajorDimensionRows, "Treat CSV records as ROWS or COLUMNS")
+	cmd.Flags().StringVar(&importCSVDelimiter, "delimiter", "", "Field delimiter: a single character, '\\t' or tab (default: from extension)")
+	cmd.Flags().BoolVar(&importCSVLazyQuotes, "lazy-quotes", false, "Accept bare quotes inside unquoted fields and unescaped quotes inside quoted fields")
+	cmd.Flags().StringVar(&importCSVComment, "comment", "", "Skip lines starting with this character")
+	cmd.Flags().StringVar(&importCSVEncoding, "encoding", "utf-8", "Input character encoding (utf-8, utf-16, utf-16be, windows-1252, iso-8859-1...)")
+	cmd.Flags().BoolVar(&importCSVSkipHeader, "skip-header", false, "Do not import the first record")
+	cmd.Flags().StringSliceVar(&importCSVSelect, "select", nil, "Source columns to import, in order (names or 1-based positions)")
+	cmd.Flags().StringSliceVar(&importCSVColumns, "columns", nil, "Map source columns to sheet columns (e.g. name:B,email:D)")
+	cmd.Flags().StringSliceVar(&importCSVTypes, "types", nil, "Column types: auto, or name:TYPE pairs with TYPE in number, date, bool, text")
+	cmd.Flags().IntVar(&importCSVChunkSize, "chunk-size", DefaultImportChunkSize, "Rows uploaded per request (0 uploads everything at once)")
+	cmd.Flags().IntVar(&importCSVResumeFrom, "resume-from", 0, "Skip the first N rows already imported by a failed run")
+	cmd.Flags().BoolVar(&importCSVResume, "resume", false, "Continue a failed chunked import from its checkpoint")
+	cmd.Flags().StringVar(&importCSVMode, "mode", "overwrite", "Import mode: overwrite, replace, append or upsert")
+	cmd.Flags().StringVar(&importCSVKeyColumn, "key-column", "A", "Key column for --mode upsert")
+	cmd.Flags().BoolVar(&importCSVCreateSheet, "create-sheet", false, "Create the sheet if it does not exist")
+	cmd.Flags().StringVar(&importCSVNewSpreadsheet, "new-spreadsheet", "", "Create a new spreadsheet with this title and import into it")
+	cmd.Flags().IntVar(&importCSVConcurrency, "concurrency", DefaultConcurrency, "Files imported in parallel in directory/glob mode")
+	cmd.MarkFlagsMutuallyExclusive("select", "columns")
+	cmd.MarkFlagsMutuallyExclusive("resume", "resume-from")
+	cmd.MarkFlagsMutuallyExclusive("resume", "new-spreadsheet")
 	return cmd
 }()
 
 func runImportCSV(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
-	spreadsheetID := args[0]
-	sheetName := args[1]
-	csvPath := args[2]
 
-	values, err := readCSV(csvPath)
+	if err := validateImportCSVFlags(); err != nil {
+		return err
+	}
+
+	var spreadsheetID, sheetName, csvPath string
+	switch {
+	case importCSVNewSpreadsheet != "" && len(args) == 2:
+		sheetName, csvPath = args[0], args[1]
+	case importCSVNewSpreadsheet != "":
+		return fmt.Errorf("with --new-spreadsheet, expected <sheet-name> <csv-path>")
+	case len(args) == 2:
+		return importCSVFiles(ctx, args[0], args[1])
+	default:
+		spreadsheetID, sheetName, csvPath = args[0], args[1], args[2]
+	}
+
+	if csvPath != "-" {
+		if _, err := os.Stat(csvPath); err != nil {
+			return fmt.Errorf("unable to open CSV file: %w", err)
+		}
+	} else if importCSVResume {
+		return fmt.Errorf("--resume needs a file, stdin cannot be read again")
+	}
+
+	service, err := auth.GetSheetsService(ctx)
 	if err != nil {
 		return err
 	}
 
+	sheetCreated := false
+	switch {
+	case importCSVNewSpreadsheet != "":
+		created, err := service.Spreadsheets.Create(&sheets.Spreadsheet{
+			Properties: &sheets.SpreadsheetProperties{Title: importCSVNewSpreadsheet},
+			Sheets: []*sheets.Sheet{
+				{Properties: &sheets.SheetProperties{Title: sheetName}},
+			},
+		}).Fields("spreadsheetId").Do()
+		if err != nil {
+			return fmt.Errorf("unable to create spreadsheet: %w", err)
+		}
+		spreadsheetID = created.SpreadsheetId
+	case importCSVCreateSheet:
+		titles, created, err := ensureSheets(service, spreadsheetID, []string{sheetName})
+		if err != nil {
+			return err
+		}
+		sheetName = titles[0]
+		sheetCreated = len(created) > 0
+	}
+
+	result, err := importCSVFile(service, spreadsheetID, sheetName, csvPath)
+	if err != nil {
+		return err
+	}
+
+	if sheetCreated {
+		result["sheet_created"] = true
+	}
+	if importCSVNewSpreadsheet != "" {
+		result["id"] = spreadsheetID
+		result["url"] = fmt.Sprintf(GoogleSheetsURLPattern, spreadsheetID)
+	}
+	return helpers.PrintJSON(result)
+}
+
+// importCSVFiles imports every file matched by a directory or glob pattern into a sheet named after
+// the file, creating missing sheets first and running up to --concurrency imports at a time
+func importCSVFiles(ctx context.Context, spreadsheetID, pattern string) error {
+	if importCSVNewSpreadsheet != "" || importCSVResumeFrom > 0 || importCSVResume {
+		return fmt.Errorf("--new-spreadsheet, --resume and --resume-from apply to a single file")
+	}
+	if importCSVConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+
+	if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+		pattern = filepath.Join(pattern, "*.csv")
+	}
+	paths, err := filepath.Glob(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no files match '%s'", pattern)
+	}
+
+	// Two files for one tab would be imported into it at the same time. Tab names are
+	// compared without case, as Sheets does
+	sheetNames := make([]string, len(paths))
+	filesOf := map[string][]string{}
+	for i, path := range paths {
+		sheetNames[i] = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		key := strings.ToLower(sheetNames[i])
+		filesOf[key] = append(filesOf[key], path)
+	}
+	var collisions []string
+	for i, name := range sheetNames {
+		if files := filesOf[strings.ToLower(name)]; len(files) > 1 && files[0] == paths[i] {
+			collisions = append(collisions, fmt.Sprintf("'%s' (%s)", name, strings.Join(files, ", ")))
+		}
+	}
+	if len(collisions) > 0 {
+		return fmt.Errorf("several files map to the same sheet: %s", strings.Join(collisions, "; "))
+	}
+
 	service, err := auth.GetSheetsService(ctx)
 	if err != nil {
 		return err
 	}
 
-	valueRange := &sheets.ValueRange{Values: values}
+	sheetNames, created, err := ensureSheets(service, spreadsheetID, sheetNames)
+	if err != nil {
+		return err
+	}
+	isCreated := map[string]bool{}
+	for _, name := range created {
+		isCreated[name] = true
+	}
+
+	results := make([]map[string]interface{}, len(paths))
+	helpers.RunParallel(importCSVConcurrency, len(paths), func(i int) {
+		result, err := importCSVFile(service, spreadsheetID, sheetNames[i], paths[i])
+		// Resuming is only possible file by file, so point at the single-file form
+		var chunkErr *importChunkError
+		if errors.As(err, &chunkErr) {
+			err = fmt.Errorf("unable to import CSV after %d rows (re-import this file alone: import-csv %s '%s' %s %s): %w",
+				chunkErr.Rows, spreadsheetID, sheetNames[i], paths[i], chunkErr.resumeFlag(), chunkErr.Err)
+		}
+		if err != nil {
+			result = map[string]interface{}{
+				"status": "error",
+				"error":  err.Error(),
+			}
+		}
+		result["file"] = paths[i]
+		result["sheet_name"] = sheetNames[i]
+		if isCreated[sheetNames[i]] {
+			result["sheet_created"] = true
+		}
+		results[i] = result
+	})
+
+	return printParallelResults("files", results, "imports")
+}
+
+// printParallelResults prints the results of jobs run in parallel under key, with an overall
+// status, and fails when any job did
+func printParallelResults(key string, results []map[string]interface{}, what string) error {
+	failed := 0
+	for _, result := range results {
+		if result["status"] == "error" {
+			failed++
+		}
+	}
+
+	status := "success"
+	if failed > 0 {
+		status = "error"
+	}
+	if err := helpers.PrintJSON(map[string]interface{}{
+		"status": status,
+		key:      results,
+	}); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d %s failed", failed, len(results), what)
+	}
+	return nil
+}
+
+// validateImportCSVFlags checks the flag combinations that do not depend on the file
+func validateImportCSVFlags() error {
+	if len(importCSVColumns) > 0 && importCSVMajorDimension != MajorDimensionRows {
+		return fmt.Errorf("--columns requires --major-dimension %s", MajorDimensionRows)
+	}
+	if len(importCSVTypes) > 0 && importCSVMajorDimension != MajorDimensionRows {
+		return fmt.Errorf("--types requires --major-dimension %s", MajorDimensionRows)
+	}
+
+	if importCSVResumeFrom < 0 {
+		return fmt.Errorf("--resume-from cannot be negative")
+	}
+
+	switch importCSVMode {
+	case "overwrite", "replace", "append":
+	case "upsert":
+		if len(importCSVColumns) > 0 || len(importCSVTypes) > 0 {
+			return fmt.Errorf("--mode upsert cannot be combined with --columns or --types")
+		}
+		if importCSVMajorDimension != MajorDimensionRows {
+			return fmt.Errorf("--mode upsert requires --major-dimension %s", MajorDimensionRows)
+		}
+	default:
+		return fmt.Errorf("invalid --mode '%s': expected overwrite, replace, append or upsert", importCSVMode)
+	}
+
+	_, err := helpers.ColumnIndex(importCSVKeyColumn)
+	return err
+}
+
+// importCSVDialect builds the dialect for one file from the import-csv flags
+func importCSVDialect(csvPath string) (csvDialect, error) {
+	dialect, err := newCSVDialect(importCSVDelimiter, csvPath)
+	if err != nil {
+		return dialect, err
+	}
+	dialect.LazyQuotes = importCSVLazyQuotes
+	dialect.Encoding, err = csvEncoding(importCSVEncoding)
+	if err != nil {
+		return dialect, err
+	}
+	if importCSVComment != "" {
+		dialect.Comment, err = parseCSVRune("comment", importCSVComment)
+		if err != nil {
+			return dialect, err
+		}
+	}
+
+	return dialect, nil
+}
+
+// importCSVFile streams one CSV file into a sheet in chunks and returns the status fields
+func importCSVFile(service *sheets.Service, spreadsheetID, sheetName, csvPath string) (map[string]interface{}, error) {
+	dialect, err := importCSVDialect(csvPath)
+	if err != nil {
+		return nil, err
+	}
+	keyCol, err := helpers.ColumnIndex(importCSVKeyColumn)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, closeCSV, err := openCSV(csvPath, dialect)
+	if err != nil {
+		return nil, err
+	}
+	defer closeCSV()
+
+	header, err := readCSVRecord(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	// The header record is buffered so it is imported unless --skip-header is set
+	var pending [][]interface{}
+	if header != nil && !importCSVSkipHeader {
+		pending = append(pending, header)
+	}
+	next := func() ([]interface{}, error) {
+		if len(pending) > 0 {
+			record := pending[0]
+			pending = pending[1:]
+			return record, nil
+		}
+		return readCSVRecord(reader)
+	}
 
-	_, err = service.Spreadsheets.Values.Update(
-		spreadsheetID,
-		fmt.Sprintf("%s!%s", sheetName, importCSVStartCell),
-		valueRange,
-	).ValueInputOption(ValueInputModeFormula).Do()
+	// Column-major imports cannot be split into row chunks
+	chunkSize := importCSVChunkSize
+	if importCSVMajorDimension != MajorDimensionRows {
+		chunkSize = 0
+	}
 
+	sheetTitle, err := helpers.ResolveSheetTitle(service, spreadsheetID, sheetName)
 	if err != nil {
-		return fmt.Errorf("unable to import CSV: %w", err)
+		return nil, err
+	}
+
+	// Chunked imports of a file are checkpointed after every chunk
+	var checkpoint, resumed *importCheckpoint
+	if chunkSize > 0 {
+		checkpoint = newImportCheckpoint(spreadsheetID, sheetTitle, csvPath)
+	}
+	resumeFrom := importCSVResumeFrom
+	if importCSVResume {
+		if checkpoint == nil {
+			return nil, fmt.Errorf("--resume applies to chunked imports of a file")
+		}
+		if resumed, err = checkpoint.load(); err != nil {
+			return nil, err
+		}
+		resumeFrom = resumed.Rows
+	}
+
+	for i := 0; i < resumeFrom; i++ {
+		record, err := next()
+		if err != nil {
+			return nil, err
+		}
+		if record == nil {
+			return nil, fmt.Errorf("cannot resume after row %d, the CSV has %d rows", resumeFrom, i)
+		}
+	}
+
+	updated, appended := 0, 0
+	var write func(blocks []csvBlock) error
+	switch {
+	case importCSVMode == "upsert":
+		write = func(blocks []csvBlock) error {
+			u, a, err := upsertRows(service, spreadsheetID, sheetTitle, keyCol, blocks[0].Values, ValueInputModeFormula)
+			updated += u
+			appended += a
+			return err
+		}
+	case len(importCSVTypes) > 0:
+		sheet, err := helpers.GetSheetProperties(service, spreadsheetID, sheetName)
+		if err != nil {
+			return nil, err
+		}
+		write = func(blocks []csvBlock) error {
+			return writeTypedCSV(service, spreadsheetID, sheet, blocks)
+		}
+	default:
+		write = func(blocks []csvBlock) error {
+			return writeCSVBlocks(service, spreadsheetID, sheetTitle, blocks)
+		}
 	}
 
-	return helpers.PrintJSON(map[string]interface{}{
+	// offset is the number of rows below --start where the next chunk goes
+	offset := resumeFrom
+	switch {
+	case resumed != nil:
+		// The interrupted run already cleared the sheet or found the last row
+		offset = resumed.Offset
+	case importCSVMode == "replace":
+		// A resumed run must keep the rows written before the failure
+		if resumeFrom == 0 {
+			_, err := service.Spreadsheets.Values.Clear(spreadsheetID, helpers.SheetRange(sheetTitle, ""), &sheets.ClearValuesRequest{}).Do()
+			if err != nil {
+				return nil, fmt.Errorf("unable to clear sheet: %w", err)
+			}
+		}
+	case importCSVMode == "append":
+		_, startRow, err := helpers.A1ToGrid(importCSVStartCell)
+		if err != nil {
+			return nil, err
+		}
+		nextRow, err := nextEmptyRow(service, spreadsheetID, sheetTitle)
+		if err != nil {
+			return nil, err
+		}
+		offset = max(0, nextRow-startRow)
+	}
+
+	// Only UTF-8 input has as many bytes read as bytes on disk, so only it gets an ETA
+	fraction := func() float64 { return -1 }
+	if info, err := os.Stat(csvPath); err == nil && info.Mode().IsRegular() && info.Size() > 0 && isUTF8Encoding(importCSVEncoding) {
+		fraction = func() float64 { return float64(reader.InputOffset()) / float64(info.Size()) }
+	}
+	label := csvPath
+	if csvPath == "-" {
+		label = "stdin"
+	}
+	progress := helpers.NewProgress(label)
+	defer progress.Stop()
+
+	imported := resumeFrom
+	chunks := 0
+	var types []string
+	if resumed != nil {
+		types = resumed.Types
+	}
+	for {
+		var chunk [][]interface{}
+		for chunkSize <= 0 || len(chunk) < chunkSize {
+			record, err := next()
+			if err != nil {
+				return nil, err
+			}
+			if record == nil {
+				break
+			}
+			chunk = append(chunk, record)
+		}
+		if len(chunk) == 0 {
+			break
+		}
+
+		// Types are inferred from the first chunk only
+		if chunks == 0 && len(importCSVTypes) > 0 && types == nil {
+			hasHeader := !importCSVSkipHeader && resumeFrom == 0
+			types, err = csvColumnTypes(header, chunk, importCSVTypes, hasHeader)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		blocks, err := csvBlocks(header, chunk, types, offset)
+		if err != nil {
+			return nil, err
+		}
+		if err := write(blocks); err != nil {
+			return nil, &importChunkError{
+				Rows:         imported,
+				Checkpointed: checkpoint != nil && (chunks > 0 || resumed != nil),
+				Err:          err,
+			}
+		}
+
+		imported += len(chunk)
+		offset += len(chunk)
+		chunks++
+		if checkpoint != nil {
+			checkpoint.Rows, checkpoint.Offset, checkpoint.Types = imported, offset, types
+			if err := checkpoint.save(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: unable to save checkpoint: %v\n", err)
+				checkpoint = nil
+			}
+		}
+		progress.Update(fraction(), fmt.Sprintf("imported %d rows (%d chunks)", imported, chunks))
+		if chunkSize <= 0 || len(chunk) < chunkSize {
+			break
+		}
+	}
+	progress.Finish(fmt.Sprintf("imported %d rows (%d chunks)", imported, chunks))
+	if checkpoint != nil {
+		checkpoint.remove()
+	}
+
+	result := map[string]interface{}{
 		"status": "success",
-		"rows":   len(values),
+		"mode":   importCSVMode,
+		"rows":   imported - resumeFrom,
+	}
+	if importCSVMode == "upsert" {
+		result["updated"] = updated
+		result["appended"] = appended
+	}
+	if resumeFrom > 0 {
+		result["resumed_from"] = resumeFrom
+	}
+	if len(importCSVColumns) > 0 {
+		result["columns"] = importCSVColumns
+	}
+	return result, nil
+}
+
+// importChunkError reports a chunk the API rejected, after Rows rows were imported
+type importChunkError struct {
+	Rows         int
+	Checkpointed bool
+	Err          error
+}
+
+func (e *importChunkError) Error() string {
+	return fmt.Sprintf("unable to import CSV after %d rows (rerun with %s): %v", e.Rows, e.resumeFlag(), e.Err)
+}
+
+// resumeFlag is the flag that continues the import where it stopped
+func (e *importChunkError) resumeFlag() string {
+	if e.Checkpointed {
+		return "--resume"
+	}
+	return fmt.Sprintf("--resume-from %d", e.Rows)
+}
+
+func (e *importChunkError) Unwrap() error {
+	return e.Err
+}
+
+// nextEmptyRow returns the 0-indexed row below the last populated row of a sheet.
+// The sheet is read one page at a time and only counted, so memory does not grow with it.
+func nextEmptyRow(service *sheets.Service, spreadsheetID, sheetTitle string) (int, error) {
+	sheet, err := helpers.GetSheetProperties(service, spreadsheetID, sheetTitle)
+	if err != nil {
+		return 0, err
+	}
+
+	rows := 0
+	err = helpers.StreamRows(service, spreadsheetID, sheet, helpers.DefaultPageSize, ValueRenderFormatted, DateRenderFormatted, func(values [][]interface{}) error {
+		rows += len(values)
+		return nil
 	})
+	if err != nil {
+		return 0, fmt.Errorf("unable to find the last populated row: %w", err)
+	}
+	return rows, nil
 }
 
-var exportCSVCmd = &cobra.Command{
-	Use:   "export-csv <spreadsheet-id> <sheet-name> <output-path>",
-	Short: "Export sheet to CSV file",
-	Args:  cobra.ExactArgs(3),
-	RunE:  runExportCSV,
+// writeCSVBlocks writes every block with one Values.BatchUpdate in USER_ENTERED mode
+func writeCSVBlocks(service *sheets.Service, spreadsheetID, sheetTitle string, blocks []csvBlock) error {
+	data := make([]*sheets.ValueRange, len(blocks))
+	for i, block := range blocks {
+		data[i] = &sheets.ValueRange{
+			Range:          helpers.SheetRange(sheetTitle, helpers.GridToA1(block.Column, block.Row)),
+			MajorDimension: importCSVMajorDimension,
+			Values:         block.Values,
+		}
+	}
+
+	_, err := service.Spreadsheets.Values.BatchUpdate(spreadsheetID, &sheets.BatchUpdateValuesRequest{
+		ValueInputOption: ValueInputModeFormula,
+		Data:             data,
+	}).Do()
+	return err
 }
 
+var (
+	exportCSVValueRender string
+	exportCSVDateRender  string
+	exportCSVDelimiter   string
+	exportCSVQuoteAll    bool
+	exportCSVCRLF        bool
+	exportCSVEncoding    string
+	exportCSVBOM         bool
+	exportCSVPageSize    int
+	exportCSVConcurrency int
+	exportCSVOutputDir   string
+)
+
+var exportCSVCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-csv <spreadsheet-id> <sheet-name> <output-path|->",
+		Short: "Export sheet to CSV file",
+		Long: `Export sheet to CSV file.
+
+The delimiter defaults to a tab for .tsv files and a comma otherwise. Fields
+are quoted only when needed unless --quote-all is set; embedded quotes are
+always doubled.
+
+An output path of "-" writes the CSV to stdout and the status JSON to stderr.
+
+The sheet is read in windows of --page-size rows and each window is written
+as soon as it arrives, so memory use stays flat on very large sheets.
+
+--encoding transcodes the output (characters the target charset cannot
+represent become its substitute character); --bom prepends a byte order
+mark, which Excel needs to detect UTF-8 files.
+
+With --output-dir DIR and only the spreadsheet ID, every grid sheet is written
+to DIR/<sheet-name>.csv (.tsv with a tab delimiter), up to --concurrency
+sheets at a time. Sheets whose names give the same file name (ignoring case)
+get their sheet ID appended.`,
+		Args: cobra.RangeArgs(1, 3),
+		RunE: runExportCSV,
+	}
+	cmd.Flags().StringVar(&exportCSVValueRender, "value-render", ValueRenderFormatted, "Value render option (FORMATTED_VALUE, UNFORMATTED_VALUE, FORMULA)")
+	cmd.Flags().StringVar(&exportCSVDateRender, "date-render", DateRenderSerial, "Date render option (SERIAL_NUMBER, FORMATTED_STRING)")
+	cmd.Flags().StringVar(&exportCSVDelimiter, "delimiter", "", "Field delimiter: a single character, '\\t' or tab (default: from extension)")
+	cmd.Flags().BoolVar(&exportCSVQuoteAll, "quote-all", false, "Quote every field")
+	cmd.Flags().BoolVar(&exportCSVCRLF, "crlf", false, "End lines with CRLF instead of LF")
+	cmd.Flags().StringVar(&exportCSVEncoding, "encoding", "utf-8", "Output character encoding (utf-8, utf-16, utf-16be, windows-1252, iso-8859-1...)")
+	cmd.Flags().BoolVar(&exportCSVBOM, "bom", false, "Write a byte order mark (UTF-8 and UTF-16 only)")
+	cmd.Flags().IntVar(&exportCSVPageSize, "page-size", helpers.DefaultPageSize, "Rows fetched per request")
+	cmd.Flags().StringVar(&exportCSVOutputDir, "output-dir", "", "Export every sheet into this directory, one file per sheet")
+	cmd.Flags().IntVar(&exportCSVConcurrency, "concurrency", DefaultConcurrency, "Sheets exported in parallel with --output-dir")
+	return cmd
+}()
+
 func runExportCSV(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
+	switch {
+	case exportCSVOutputDir != "" && len(args) == 1:
+		return exportCSVSheets(ctx, args[0], exportCSVOutputDir)
+	case exportCSVOutputDir != "":
+		return fmt.Errorf("with --output-dir, expected only <spreadsheet-id>")
+	case len(args) != 3:
+		return fmt.Errorf("expected <spreadsheet-id> <sheet-name> <output-path|->, or <spreadsheet-id> --output-dir DIR")
+	}
 	spreadsheetID := args[0]
 	sheetName := args[1]
 	outputPath := args[2]
 
+	dialect, err := exportCSVDialect(outputPath)
+	if err != nil {
+		return err
+	}
+
 	service, err := auth.GetSheetsService(ctx)
 	if err != nil {
 		return err
 	}
 
-	resp, err := service.Spreadsheets.Values.Get(spreadsheetID, sheetName).Do()
+	sheet, err := helpers.GetSheetProperties(service, spreadsheetID, sheetName)
 	if err != nil {
-		return fmt.Errorf("unable to get sheet data: %w", err)
+		return err
 	}
 
-	if err := writeCSV(outputPath, resp.Values); err != nil {
+	rows, err := exportCSVSheet(service, spreadsheetID, sheet, outputPath, dialect)
+	if err != nil {
 		return err
 	}
 
-	return helpers.PrintJSON(map[string]string{
+	status := map[string]interface{}{
 		"status": "success",
 		"file":   outputPath,
+		"rows":   rows,
+	}
+	if outputPath == "-" {
+		return helpers.FprintJSON(os.Stderr, status)
+	}
+	return helpers.PrintJSON(status)
+}
+
+// exportCSVDialect builds the output dialect from the export-csv flags
+func exportCSVDialect(outputPath string) (csvDialect, error) {
+	dialect, err := newCSVDialect(exportCSVDelimiter, outputPath)
+	if err != nil {
+		return dialect, err
+	}
+	dialect.QuoteAll = exportCSVQuoteAll
+	dialect.UseCRLF = exportCSVCRLF
+	dialect.BOM = exportCSVBOM
+	dialect.Encoding, err = csvEncoding(exportCSVEncoding)
+	if err != nil {
+		return dialect, err
+	}
+	if dialect.BOM && !isUnicodeEncoding(exportCSVEncoding) {
+		return dialect, fmt.Errorf("--bom requires a UTF-8 or UTF-16 encoding")
+	}
+	return dialect, nil
+}
+
+// exportCSVSheet streams one sheet to a CSV file and returns the number of rows written
+func exportCSVSheet(service *sheets.Service, spreadsheetID string, sheet *sheets.SheetProperties, outputPath string, dialect csvDialect) (int, error) {
+	output, closeCSV, err := createCSV(outputPath, dialect)
+	if err != nil {
+		return 0, err
+	}
+
+	// The grid size is not the extent of the data, so there is no total to show a fraction of
+	progress := helpers.NewProgress(sheet.Title)
+	defer progress.Stop()
+
+	rows := 0
+	err = helpers.StreamRows(service, spreadsheetID, sheet, exportCSVPageSize, exportCSVValueRender, exportCSVDateRender, func(values [][]interface{}) error {
+		rows += len(values)
+		if err := writeCSVRecords(output, values, dialect); err != nil {
+			return err
+		}
+		progress.Update(-1, fmt.Sprintf("exported %d rows", rows))
+		return nil
 	})
+	if err != nil {
+		closeCSV()
+		return rows, err
+	}
+	progress.Finish(fmt.Sprintf("exported %d rows", rows))
+	return rows, closeCSV()
 }
 
-func readCSV(path string) ([][]interface{}, error) {
-	file, err := os.Open(path)
+// exportCSVSheets writes every grid sheet to a file named after it in dir, running up to
+// --concurrency exports at a time
+func exportCSVSheets(ctx context.Context, spreadsheetID, dir string) error {
+	if exportCSVConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+
+	dialect, err := exportCSVDialect(".csv")
 	if err != nil {
-		return nil, fmt.Errorf("unable to open CSV file: %w", err)
+		return err
+	}
+	extension := ".csv"
+	if dialect.Delimiter == '\t' {
+		extension = ".tsv"
 	}
-	defer file.Close()
 
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
+	service, err := auth.GetSheetsService(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read CSV: %w", err)
+		return err
+	}
+
+	spreadsheet, err := helpers.GetSpreadsheetSheets(service, spreadsheetID)
+	if err != nil {
+		return fmt.Errorf("unable to get spreadsheet: %w", err)
+	}
+
+	var grids []*sheets.SheetProperties
+	for _, sheet := range spreadsheet.Sheets {
+		if sheet.Properties.SheetType == "" || sheet.Properties.SheetType == "GRID" {
+			grids = append(grids, sheet.Properties)
+		}
+	}
+	if len(grids) == 0 {
+		return fmt.Errorf("spreadsheet has no grid sheets")
+	}
+
+	// Two sheets writing one file would interleave, so names are made unique first, ignoring
+	// case for case-insensitive file systems
+	paths := make([]string, len(grids))
+	used := map[string]bool{}
+	for i, sheet := range grids {
+		name := csvFileName(sheet.Title)
+		if used[strings.ToLower(name)] {
+			name = fmt.Sprintf("%s_%d", name, sheet.SheetId)
+		}
+		if used[strings.ToLower(name)] {
+			return fmt.Errorf("sheet '%s' has no file name of its own in %s", sheet.Title, dir)
+		}
+		used[strings.ToLower(name)] = true
+		paths[i] = filepath.Join(dir, name+extension)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("unable to create output directory: %w", err)
+	}
+
+	results := make([]map[string]interface{}, len(grids))
+	helpers.RunParallel(exportCSVConcurrency, len(grids), func(i int) {
+		path := paths[i]
+		result := map[string]interface{}{
+			"status":     "success",
+			"file":       path,
+			"sheet_name": grids[i].Title,
+		}
+		rows, err := exportCSVSheet(service, spreadsheetID, grids[i], path, dialect)
+		if err != nil {
+			result["status"] = "error"
+			result["error"] = err.Error()
+		}
+		result["rows"] = rows
+		results[i] = result
+	})
+
+	return printParallelResults("files", results, "exports")
+}
+
+// csvFileName turns a sheet title into a file name, replacing characters that are not
+// allowed in file names on some systems
+func csvFileName(title string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`<>:"/\|?*`, r) || r < ' ' {
+			return '_'
+		}
+		return r
+	}, title)
+}
+
+// csvDialect describes how CSV records are delimited, quoted and encoded
+type csvDialect struct {
+	Delimiter  rune
+	Comment    rune
+	LazyQuotes bool
+	QuoteAll   bool
+	UseCRLF    bool
+	Encoding   encoding.Encoding
+	BOM        bool
+}
+
+// newCSVDialect resolves the delimiter flag, falling back to a tab for .tsv paths and a comma otherwise
+func newCSVDialect(delimiter, path string) (csvDialect, error) {
+	dialect := csvDialect{Delimiter: ',', Encoding: unicode.UTF8}
+	if delimiter == "" {
+		if strings.EqualFold(filepath.Ext(path), ".tsv") {
+			dialect.Delimiter = '\t'
+		}
+		return dialect, nil
+	}
+
+	r, err := parseCSVRune("delimiter", delimiter)
+	if err != nil {
+		return dialect, err
+	}
+	if r == '"' || r == '\r' || r == '\n' {
+		return dialect, fmt.Errorf("invalid delimiter %q", delimiter)
+	}
+	dialect.Delimiter = r
+	return dialect, nil
+}
+
+// parseCSVRune accepts a single character, or \t / tab for a tab
+func parseCSVRune(name, value string) (rune, error) {
+	switch strings.ToLower(value) {
+	case "\\t", "tab":
+		return '\t', nil
+	}
+	if utf8.RuneCountInString(value) != 1 {
+		return 0, fmt.Errorf("invalid %s '%s': expected a single character", name, value)
+	}
+	r, _ := utf8.DecodeRuneInString(value)
+	return r, nil
+}
+
+// csvEncoding resolves an encoding name; "utf-16" means little-endian, as written by Windows tools
+func csvEncoding(name string) (encoding.Encoding, error) {
+	switch strings.ToLower(name) {
+	case "", "utf-8", "utf8":
+		return unicode.UTF8, nil
+	case "utf-16", "utf-16le":
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), nil
+	case "utf-16be":
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM), nil
+	}
+
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported encoding '%s'", name)
+	}
+	return enc, nil
+}
+
+func isUTF8Encoding(name string) bool {
+	return strings.ReplaceAll(strings.ToLower(name), "-", "") == "utf8"
+}
+
+func isUnicodeEncoding(name string) bool {
+	return strings.HasPrefix(strings.ReplaceAll(strings.ToLower(name), "-", ""), "utf")
+}
+
+// csvColumnIndex resolves a source column given by 1-based position or header name
+func csvColumnIndex(header []interface{}, ref string) (int, error) {
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 1 {
+			return 0, fmt.Errorf("invalid column position %d: positions start at 1", n)
+		}
+		return n - 1, nil
 	}
 
-	values := make([][]interface{}, len(records))
-	for i, record := range records {
-		row := make([]interface{}, len(record))
-		for j, v := range record {
-			row[j] = v
+	for i, cell := range header {
+		if helpers.CellString(cell) == ref {
+			return i, nil
 		}
-		values[i] = row
 	}
+	return 0, fmt.Errorf("column '%s' not found in CSV header", ref)
+}
+
+// selectCSVColumns keeps the given source columns in order; missing cells become empty strings
+func selectCSVColumns(values [][]interface{}, indices []int) [][]interface{} {
+	selected := make([][]interface{}, len(values))
+	for i, row := range values {
+		out := make([]interface{}, len(indices))
+		for j, index := range indices {
+			out[j] = ""
+			if index < len(row) {
+				out[j] = row[index]
+			}
+		}
+		selected[i] = out
+	}
+	return selected
+}
 
-	return values, nil
+// csvBlock is a rectangle of CSV values written at a 0-indexed sheet position, with one type per column
+type csvBlock struct {
+	Column int
+	Row    int
+	Values [][]interface{}
+	Types  []string
 }
 
-func writeCSV(path string, values [][]interface{}) error {
-	file, err := os.Create(path)
+// csvBlocks lays out the imported values, rowOffset rows below --start: one block at --start (after --select), or one
+// single-column block per --columns "source:COLUMN" mapping. types is indexed by source column.
+func csvBlocks(header []interface{}, values [][]interface{}, types []string, rowOffset int) ([]csvBlock, error) {
+	startCol, startRow, err := helpers.A1ToGrid(importCSVStartCell)
 	if err != nil {
-		return fmt.Errorf("unable to create CSV file: %w", err)
+		return nil, err
+	}
+	startRow += rowOffset
+
+	typesOf := func(indices []int) []string {
+		if types == nil {
+			return nil
+		}
+		selected := make([]string, len(indices))
+		for i, index := range indices {
+			if index < len(types) {
+				selected[i] = types[index]
+			}
+		}
+		return selected
+	}
+
+	if len(importCSVColumns) == 0 {
+		if len(importCSVSelect) == 0 {
+			return []csvBlock{{Column: startCol, Row: startRow, Values: values, Types: types}}, nil
+		}
+
+		indices := make([]int, len(importCSVSelect))
+		for i, ref := range importCSVSelect {
+			if indices[i], err = csvColumnIndex(header, ref); err != nil {
+				return nil, err
+			}
+		}
+		return []csvBlock{{
+			Column: startCol,
+			Row:    startRow,
+			Values: selectCSVColumns(values, indices),
+			Types:  typesOf(indices),
+		}}, nil
 	}
-	defer file.Close()
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	blocks := make([]csvBlock, 0, len(importCSVColumns))
+	for _, mapping := range importCSVColumns {
+		sep := strings.LastIndex(mapping, ":")
+		if sep <= 0 || sep == len(mapping)-1 {
+			return nil, fmt.Errorf("invalid column mapping '%s': expected source:COLUMN", mapping)
+		}
+
+		source, err := csvColumnIndex(header, mapping[:sep])
+		if err != nil {
+			return nil, err
+		}
+		target, err := helpers.ColumnIndex(mapping[sep+1:])
+		if err != nil {
+			return nil, err
+		}
+
+		blocks = append(blocks, csvBlock{
+			Column: target,
+			Row:    startRow,
+			Values: selectCSVColumns(values, []int{source}),
+			Types:  typesOf([]int{source}),
+		})
+	}
+
+	return blocks, nil
+}
+
+// csvColumnTypes resolves --types into one type per source column, inferring the unlisted ones.
+// When hasHeader is set the first record is left out of inference.
+func csvColumnTypes(header []interface{}, values [][]interface{}, specs []string, hasHeader bool) ([]string, error) {
+	width := 0
+	for _, row := range values {
+		width = max(width, len(row))
+	}
+
+	types := make([]string, width)
+	explicit := make([]bool, width)
+	for _, spec := range specs {
+		if spec == "auto" {
+			continue
+		}
+
+		sep := strings.LastIndex(spec, ":")
+		if sep <= 0 {
+			return nil, fmt.Errorf("invalid type '%s': expected auto or name:TYPE", spec)
+		}
+		typ := strings.ToLower(spec[sep+1:])
+		switch typ {
+		case "number", "date", "bool", "text", "auto":
+		default:
+			return nil, fmt.Errorf("invalid type '%s' for %s: expected number, date, bool, text or auto", typ, spec[:sep])
+		}
+
+		index, err := csvColumnIndex(header, spec[:sep])
+		if err != nil {
+			return nil, err
+		}
+		if index >= width {
+			continue
+		}
+		if typ != "auto" {
+			types[index] = typ
+			explicit[index] = true
+		}
+	}
+
+	rows := values
+	if hasHeader && len(rows) > 0 {
+		rows = rows[1:]
+	}
+	for i := range types {
+		if !explicit[i] {
+			types[i] = inferCSVType(rows, i)
+		}
+	}
+
+	return types, nil
+}
+
+// inferCSVType returns the type shared by every non-empty cell of a column, or "" when they differ
+func inferCSVType(values [][]interface{}, col int) string {
+	kind := ""
+	for _, row := range values {
+		if col >= len(row) {
+			continue
+		}
+		value := strings.TrimSpace(helpers.CellString(row[col]))
+		if value == "" {
+			continue
+		}
+
+		cellKind := ""
+		if _, ok := parseCSVNumber(value); ok {
+			// Leading zeros mark identifiers (e.g. 00123) that a number would lose
+			if hasLeadingZero(value) {
+				return ""
+			}
+			cellKind = "number"
+		} else if _, ok := parseCSVBool(value); ok {
+			cellKind = "bool"
+		} else if _, _, ok := parseCSVDate(value); ok {
+			cellKind = "date"
+		} else {
+			return ""
+		}
+
+		if kind != "" && kind != cellKind {
+			return ""
+		}
+		kind = cellKind
+	}
+	return kind
+}
+
+// csvNumberPattern is the decimal syntax accepted for numbers; ParseFloat alone also takes NaN, Inf and hex floats
+var csvNumberPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// parseCSVNumber parses a finite decimal number
+func parseCSVNumber(value string) (float64, bool) {
+	if !csvNumberPattern.MatchString(value) {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, false
+	}
+	return n, true
+}
+
+// csvDecimalPattern returns a number pattern keeping the decimal places written in the source,
+// or "" for integers and exponent notation so they keep the default display
+func csvDecimalPattern(value string) string {
+	if strings.ContainsAny(value, "eE") {
+		return ""
+	}
+	_, decimals, ok := strings.Cut(value, ".")
+	if !ok || decimals == "" {
+		return ""
+	}
+	return "0." + strings.Repeat("0", len(decimals))
+}
+
+// hasLeadingZero reports whether the integer part of a number has a superfluous leading zero (e.g. 007, -01.5)
+func hasLeadingZero(value string) bool {
+	digits := strings.TrimLeft(value, "+-")
+	return len(digits) > 1 && digits[0] == '0' && digits[1] >= '0' && digits[1] <= '9'
+}
+
+func parseCSVBool(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	}
+	return false, false
+}
+
+// parseCSVDate parses an ISO 8601 date or date-time into a Sheets serial number
+func parseCSVDate(value string) (serial float64, hasTime bool, ok bool) {
+	for _, layout := range []string{"2006-01-02", "2006-01-02 15:04:05", "2006-01-02T15:04:05", time.RFC3339} {
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			continue
+		}
+		// Serial numbers count days from 1899-12-30 in wall-clock time
+		wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+		return wall.Sub(sheetsEpoch).Hours() / 24, layout != "2006-01-02", true
+	}
+	return 0, false, false
+}
+
+var sheetsEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// typedCSVCell converts a CSV value to cell data of the given type, falling back to text or a formula
+func typedCSVCell(value interface{}, typ string) *sheets.CellData {
+	s := helpers.CellString(value)
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return &sheets.CellData{}
+	}
+
+	switch typ {
+	case "number":
+		if n, ok := parseCSVNumber(trimmed); ok {
+			cell := &sheets.CellData{
+				UserEnteredValue: &sheets.ExtendedValue{NumberValue: &n},
+			}
+			if pattern := csvDecimalPattern(trimmed); pattern != "" {
+				format := &sheets.NumberFormat{Type: helpers.FormatTypeNumber, Pattern: pattern}
+				cell.UserEnteredFormat = &sheets.CellFormat{NumberFormat: format}
+			}
+			return cell
+		}
+	case "bool":
+		if b, ok := parseCSVBool(trimmed); ok {
+			return &sheets.CellData{
+				UserEnteredValue: &sheets.ExtendedValue{BoolValue: &b},
+			}
+		}
+	case "date":
+		if serial, hasTime, ok := parseCSVDate(trimmed); ok {
+			format := &sheets.NumberFormat{Type: helpers.FormatTypeDate, Pattern: helpers.GetDefaultFormatPattern(helpers.FormatTypeDate)}
+			if hasTime {
+				format = &sheets.NumberFormat{Type: "DATE_TIME", Pattern: "yyyy-mm-dd hh:mm:ss"}
+			}
+			return &sheets.CellData{
+				UserEnteredValue:  &sheets.ExtendedValue{NumberValue: &serial},
+				UserEnteredFormat: &sheets.CellFormat{NumberFormat: format},
+			}
+		}
+	case "text":
+		return &sheets.CellData{
+			UserEnteredValue:  &sheets.ExtendedValue{StringValue: &s},
+			UserEnteredFormat: &sheets.CellFormat{NumberFormat: &sheets.NumberFormat{Type: "TEXT"}},
+		}
+	}
+
+	if strings.HasPrefix(s, "=") {
+		return &sheets.CellData{
+			UserEnteredValue: &sheets.ExtendedValue{FormulaValue: &s},
+		}
+	}
+	return &sheets.CellData{
+		UserEnteredValue: &sheets.ExtendedValue{StringValue: &s},
+	}
+}
+
+// writeTypedCSV writes every block as typed cells with UpdateCells, growing the grid first when needed
+func writeTypedCSV(service *sheets.Service, spreadsheetID string, sheet *sheets.SheetProperties, blocks []csvBlock) error {
+	var requests []*sheets.Request
+	rowsNeeded, colsNeeded := 0, 0
+
+	for _, block := range blocks {
+		rows := make([]*sheets.RowData, len(block.Values))
+		for i, record := range block.Values {
+			cells := make([]*sheets.CellData, len(record))
+			for j, value := range record {
+				typ := ""
+				if j < len(block.Types) {
+					typ = block.Types[j]
+				}
+				cells[j] = typedCSVCell(value, typ)
+			}
+			rows[i] = &sheets.RowData{Values: cells}
+			colsNeeded = max(colsNeeded, block.Column+len(record))
+		}
+		rowsNeeded = max(rowsNeeded, block.Row+len(block.Values))
+
+		requests = append(requests, &sheets.Request{
+			UpdateCells: &sheets.UpdateCellsRequest{
+				Start: &sheets.GridCoordinate{
+					SheetId:         sheet.SheetId,
+					RowIndex:        int64(block.Row),
+					ColumnIndex:     int64(block.Column),
+					ForceSendFields: []string{"SheetId", "RowIndex", "ColumnIndex"},
+				},
+				Rows:   rows,
+				Fields: "userEnteredValue,userEnteredFormat.numberFormat",
+			},
+		})
+	}
+
+	// Unlike Values.Update, UpdateCells does not grow the sheet
+	var grow []*sheets.Request
+	if grid := sheet.GridProperties; grid != nil {
+		if extra := int64(rowsNeeded) - grid.RowCount; extra > 0 {
+			grow = append(grow, &sheets.Request{
+				AppendDimension: &sheets.AppendDimensionRequest{SheetId: sheet.SheetId, Dimension: MajorDimensionRows, Length: extra},
+			})
+		}
+		if extra := int64(colsNeeded) - grid.ColumnCount; extra > 0 {
+			grow = append(grow, &sheets.Request{
+				AppendDimension: &sheets.AppendDimensionRequest{SheetId: sheet.SheetId, Dimension: MajorDimensionColumns, Length: extra},
+			})
+		}
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: append(grow, requests...),
+	}
+
+	if _, err := service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do(); err != nil {
+		return err
+	}
+
+	// Keep the grid size current for the next chunk
+	if grid := sheet.GridProperties; grid != nil {
+		grid.RowCount = max(grid.RowCount, int64(rowsNeeded))
+		grid.ColumnCount = max(grid.ColumnCount, int64(colsNeeded))
+	}
+	return nil
+}
+
+// openCSV returns a streaming reader over a CSV file, or stdin when path is "-"
+func openCSV(path string, dialect csvDialect) (*csv.Reader, func(), error) {
+	var input io.Reader = os.Stdin
+	closeFile := func() {}
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to open CSV file: %w", err)
+		}
+		input = file
+		closeFile = func() { file.Close() }
+	}
+
+	decoder := unicode.BOMOverride(dialect.Encoding.NewDecoder())
+	reader := csv.NewReader(transform.NewReader(input, decoder))
+	reader.Comma = dialect.Delimiter
+	reader.Comment = dialect.Comment
+	reader.LazyQuotes = dialect.LazyQuotes
+	reader.ReuseRecord = true
+
+	return reader, closeFile, nil
+}
+
+// readCSVRecord reads the next record as a row, returning nil at the end of the file
+func readCSVRecord(reader *csv.Reader) ([]interface{}, error) {
+	record, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CSV: %w", err)
+	}
+
+	row := make([]interface{}, len(record))
+	for i, v := range record {
+		row[i] = v
+	}
+	return row, nil
+}
+
+// readCSV reads a whole CSV file, or stdin when path is "-"
+func readCSV(path string, dialect csvDialect) ([][]interface{}, error) {
+	reader, closeCSV, err := openCSV(path, dialect)
+	if err != nil {
+		return nil, err
+	}
+	defer closeCSV()
+
+	var values [][]interface{}
+	for {
+		row, err := readCSVRecord(reader)
+		if err != nil {
+			return nil, err
+		}
+		if row == nil {
+			return values, nil
+		}
+		values = append(values, row)
+	}
+}
+
+// createCSV opens a transcoding writer on a CSV file, or stdout when path is "-", and writes the
+// optional byte order mark. The returned function flushes the encoder and closes the file.
+func createCSV(path string, dialect csvDialect) (io.Writer, func() error, error) {
+	var output io.Writer = os.Stdout
+	closeFile := func() error { return nil }
+	if path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to create CSV file: %w", err)
+		}
+		output = file
+		closeFile = file.Close
+	}
+
+	encoder := transform.NewWriter(output, encoding.ReplaceUnsupported(dialect.Encoding.NewEncoder()))
+	closeCSV := func() error {
+		err := encoder.Close()
+		if closeErr := closeFile(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("unable to write CSV: %w", err)
+		}
+		return nil
+	}
+
+	if dialect.BOM {
+		if _, err := io.WriteString(encoder, "\uFEFF"); err != nil {
+			closeCSV()
+			return nil, nil, fmt.Errorf("unable to write CSV: %w", err)
+		}
+	}
+
+	return encoder, closeCSV, nil
+}
+
+// writeCSVRecords writes records to output, before transcoding
+func writeCSVRecords(output io.Writer, values [][]interface{}, dialect csvDialect) error {
+	if dialect.QuoteAll {
+		return writeQuotedCSV(output, values, dialect)
+	}
+
+	writer := csv.NewWriter(output)
+	writer.Comma = dialect.Delimiter
+	writer.UseCRLF = dialect.UseCRLF
 
 	for _, row := range values {
 		record := make([]string, len(row))
 		for i, cell := range row {
-			record[i] = fmt.Sprintf("%v", cell)
+			record[i] = helpers.CellString(cell)
 		}
 		if err := writer.Write(record); err != nil {
 			return fmt.Errorf("unable to write CSV row: %w", err)
 		}
 	}
 
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("unable to write CSV: %w", err)
+	}
+	return nil
+}
+
+// writeQuotedCSV writes every field quoted, which encoding/csv cannot do
+func writeQuotedCSV(output io.Writer, values [][]interface{}, dialect csvDialect) error {
+	lineEnd := "\n"
+	if dialect.UseCRLF {
+		lineEnd = "\r\n"
+	}
+
+	writer := bufio.NewWriter(output)
+	for _, row := range values {
+		for i, cell := range row {
+			if i > 0 {
+				writer.WriteRune(dialect.Delimiter)
+			}
+			writer.WriteString(`"` + strings.ReplaceAll(helpers.CellString(cell), `"`, `""`) + `"`)
+		}
+		writer.WriteString(lineEnd)
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("unable to write CSV: %w", err)
+	}
 	return nil
 }
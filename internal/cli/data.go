@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"google.golang.org/api/sheets/v4"
@@ -12,7 +13,11 @@ import (
 	"spreadsheet-manager/internal/helpers"
 )
 
-var addDataFormulaMode bool
+var (
+	addDataFormulaMode    bool
+	addDataMajorDimension string
+	addDataBooleans       bool
+)
 
 var addDataCmd = func() *cobra.Command {
 	cmd := &cobra.Command{
@@ -22,6 +27,8 @@ var addDataCmd = func() *cobra.Command {
 		RunE:  runAddData,
 	}
 	cmd.Flags().BoolVar(&addDataFormulaMode, "formula", true, "Enable formula mode (USER_ENTERED)")
+	cmd.Flags().StringVar(&addDataMajorDimension, "major-dimension", MajorDimensionRows, "Major dimension of the values (ROWS, COLUMNS)")
+	cmd.Flags().BoolVar(&addDataBooleans, "booleans", false, "Write \"true\"/\"false\" strings as real booleans (e.g. for checkboxes)")
 	return cmd
 }()
 
@@ -42,16 +49,28 @@ func runAddData(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid JSON values: %w", err)
 	}
 
+	if addDataBooleans {
+		convertBooleanStrings(values)
+	}
+
+	sheetTitle, err := helpers.ResolveSheetTitle(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
 	valueInputOption := ValueInputModeFormula
 	if !addDataFormulaMode {
 		valueInputOption = ValueInputModeRaw
 	}
 
-	valueRange := &sheets.ValueRange{Values: values}
+	valueRange := &sheets.ValueRange{
+		MajorDimension: addDataMajorDimension,
+		Values:         values,
+	}
 
 	_, err = service.Spreadsheets.Values.Update(
 		spreadsheetID,
-		fmt.Sprintf("%s!%s", sheetName, rangeA1),
+		helpers.SheetRange(sheetTitle, rangeA1),
 		valueRange,
 	).ValueInputOption(valueInputOption).Do()
 
@@ -61,6 +80,363 @@ func runAddData(cmd *cobra.Command, args []string) error {
 
 	return helpers.PrintJSON(map[string]string{
 		"status": "success",
-		"range":  fmt.Sprintf("%s!%s", sheetName, rangeA1),
+		"range":  helpers.SheetRange(sheetTitle, rangeA1),
+	})
+}
+
+// convertBooleanStrings replaces "true"/"false" strings (any case) with booleans in place
+func convertBooleanStrings(values [][]interface{}) {
+	for _, row := range values {
+		for j, cell := range row {
+			if s, ok := cell.(string); ok {
+				switch strings.ToLower(strings.TrimSpace(s)) {
+				case "true":
+					row[j] = true
+				case "false":
+					row[j] = false
+				}
+			}
+		}
+	}
+}
+
+var getFormulasCmd = &cobra.Command{
+	Use:   "get-formulas <spreadsheet-id> <sheet-name> [range]",
+	Short: "Get cell formulas keyed by A1 address",
+	Args:  cobra.RangeArgs(2, 3),
+	RunE:  runGetFormulas,
+}
+
+func runGetFormulas(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetTitle, err := helpers.ResolveSheetTitle(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	readRange := helpers.SheetRange(sheetTitle, "")
+	if len(args) > 2 {
+		readRange = helpers.SheetRange(sheetTitle, args[2])
+	}
+
+	resp, err := service.Spreadsheets.Values.Get(spreadsheetID, readRange).
+		ValueRenderOption(ValueRenderFormula).Do()
+	if err != nil {
+		return fmt.Errorf("unable to get formulas: %w", err)
+	}
+
+	startCol, startRow, _, _, err := helpers.ParseRange(helpers.StripSheetName(resp.Range))
+	if err != nil {
+		return err
+	}
+
+	formulas := map[string]string{}
+	for i, row := range resp.Values {
+		for j, cell := range row {
+			value, ok := cell.(string)
+			if !ok || !strings.HasPrefix(value, "=") {
+				continue
+			}
+			formulas[helpers.GridToA1(startCol+j, startRow+i)] = value
+		}
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":   "success",
+		"range":    resp.Range,
+		"count":    len(formulas),
+		"formulas": formulas,
+	})
+}
+
+var (
+	upsertRowsKeyColumn   string
+	upsertRowsCSVPath     string
+	upsertRowsFormulaMode bool
+)
+
+var upsertRowsCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upsert-rows <spreadsheet-id> <sheet-name> [values-json]",
+		Short: "Update rows matching a key column and append the rest",
+		Args:  cobra.RangeArgs(2, 3),
+		RunE:  runUpsertRows,
+	}
+	cmd.Flags().StringVar(&upsertRowsKeyColumn, "key-column", "A", "Column holding the row key")
+	cmd.Flags().StringVar(&upsertRowsCSVPath, "csv", "", "Read incoming rows from a CSV file instead of JSON")
+	cmd.Flags().BoolVar(&upsertRowsFormulaMode, "formula", true, "Enable formula mode (USER_ENTERED)")
+	return cmd
+}()
+
+func runUpsertRows(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	var rows [][]interface{}
+	switch {
+	case upsertRowsCSVPath != "" && len(args) > 2:
+		return fmt.Errorf("provide either values-json or --csv, not both")
+	case upsertRowsCSVPath != "":
+		dialect, err := newCSVDialect("", upsertRowsCSVPath)
+		if err != nil {
+			return err
+		}
+		values, err := readCSV(upsertRowsCSVPath, dialect)
+		if err != nil {
+			return err
+		}
+		rows = values
+	case len(args) > 2:
+		if err := json.Unmarshal([]byte(args[2]), &rows); err != nil {
+			return fmt.Errorf("invalid JSON values: %w", err)
+		}
+	default:
+		return fmt.Errorf("values-json argument or --csv is required")
+	}
+
+	keyCol, err := helpers.ColumnIndex(upsertRowsKeyColumn)
+	if err != nil {
+		return err
+	}
+
+	valueInputOption := ValueInputModeFormula
+	if !upsertRowsFormulaMode {
+		valueInputOption = ValueInputModeRaw
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetTitle, err := helpers.ResolveSheetTitle(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	updated, appended, err := upsertRows(service, spreadsheetID, sheetTitle, keyCol, rows, valueInputOption)
+	if err != nil {
+		return err
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":   "success",
+		"updated":  updated,
+		"appended": appended,
+	})
+}
+
+// upsertRows overwrites rows whose key column matches an incoming row and appends the others.
+// It issues at most three calls: one read of the key column, one batch update, and one append.
+func upsertRows(service *sheets.Service, spreadsheetID, sheetTitle string, keyCol int, rows [][]interface{}, valueInputOption string) (int, int, error) {
+	keyLetters := helpers.ColumnToLetters(keyCol)
+	resp, err := service.Spreadsheets.Values.Get(
+		spreadsheetID,
+		helpers.SheetRange(sheetTitle, keyLetters+":"+keyLetters),
+	).ValueRenderOption(ValueRenderUnformatted).Do()
+	if err != nil {
+		return 0, 0, fmt.Errorf("unable to read key column: %w", err)
+	}
+
+	existing := map[string]int{}
+	for i, row := range resp.Values {
+		if len(row) == 0 {
+			continue
+		}
+		key := helpers.CellString(row[0])
+		if _, ok := existing[key]; !ok {
+			existing[key] = i
+		}
+	}
+
+	updates := map[int][]interface{}{}
+	var updateOrder []int
+	pending := map[string]int{}
+	var newRows [][]interface{}
+
+	for _, row := range rows {
+		if keyCol >= len(row) {
+			return 0, 0, fmt.Errorf("row %v has no value in key column %s", row, keyLetters)
+		}
+		key := helpers.CellString(row[keyCol])
+
+		if index, ok := existing[key]; ok {
+			if _, seen := updates[index]; !seen {
+				updateOrder = append(updateOrder, index)
+			}
+			updates[index] = row
+			continue
+		}
+
+		if index, ok := pending[key]; ok {
+			newRows[index] = row
+			continue
+		}
+		pending[key] = len(newRows)
+		newRows = append(newRows, row)
+	}
+
+	if len(updateOrder) > 0 {
+		data := make([]*sheets.ValueRange, 0, len(updateOrder))
+		for _, index := range updateOrder {
+			data = append(data, &sheets.ValueRange{
+				Range:  helpers.SheetRange(sheetTitle, helpers.GridToA1(0, index)),
+				Values: [][]interface{}{updates[index]},
+			})
+		}
+
+		_, err := service.Spreadsheets.Values.BatchUpdate(spreadsheetID, &sheets.BatchUpdateValuesRequest{
+			ValueInputOption: valueInputOption,
+			Data:             data,
+		}).Do()
+		if err != nil {
+			return 0, 0, fmt.Errorf("unable to update existing rows: %w", err)
+		}
+	}
+
+	if len(newRows) > 0 {
+		_, err := service.Spreadsheets.Values.Append(
+			spreadsheetID,
+			helpers.SheetRange(sheetTitle, DefaultStartCell),
+			&sheets.ValueRange{Values: newRows},
+		).ValueInputOption(valueInputOption).InsertDataOption(InsertDataOptionRows).Do()
+		if err != nil {
+			return 0, 0, fmt.Errorf("unable to append new rows: %w", err)
+		}
+	}
+
+	return len(updateOrder), len(newRows), nil
+}
+
+var setHyperlinkFile string
+
+var setHyperlinkCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-hyperlink <spreadsheet-id> <sheet-name> [<cell> <url> [text]]",
+		Short: "Write a link with display text into a cell, or many from a file",
+		Long: `Write a link with display text into a cell (the URL itself when no text is
+given). The cell holds plain text linked through a text format run, so it
+sorts and filters like any text value.
+
+With --file, cell,url,text lines are read from a CSV file (text optional) and
+written in a single batch update.`,
+		Args: cobra.RangeArgs(2, 5),
+		RunE: runSetHyperlink,
+	}
+	cmd.Flags().StringVar(&setHyperlinkFile, "file", "", "CSV file of cell,url,text lines (- for stdin)")
+	return cmd
+}()
+
+func runSetHyperlink(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	var links [][]interface{}
+	switch {
+	case setHyperlinkFile != "" && len(args) > 2:
+		return fmt.Errorf("provide either a cell and url or --file, not both")
+	case setHyperlinkFile != "":
+		dialect, err := newCSVDialect("", setHyperlinkFile)
+		if err != nil {
+			return err
+		}
+		links, err = readCSV(setHyperlinkFile, dialect)
+		if err != nil {
+			return err
+		}
+	case len(args) >= 4:
+		link := make([]interface{}, 0, 3)
+		for _, arg := range args[2:] {
+			link = append(link, arg)
+		}
+		links = [][]interface{}{link}
+	default:
+		return fmt.Errorf("a cell and url, or --file, are required")
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	requests := make([]*sheets.Request, 0, len(links))
+	for i, link := range links {
+		if len(link) < 2 {
+			return fmt.Errorf("link %d: expected cell,url[,text]", i+1)
+		}
+		cell := strings.ToUpper(strings.TrimSpace(helpers.CellString(link[0])))
+		uri := strings.TrimSpace(helpers.CellString(link[1]))
+		text := uri
+		if len(link) > 2 && helpers.CellString(link[2]) != "" {
+			text = helpers.CellString(link[2])
+		}
+
+		col, row, err := helpers.A1ToGrid(cell)
+		if err != nil || col < 0 || row < 0 {
+			return fmt.Errorf("link %d: invalid cell reference '%s'", i+1, cell)
+		}
+		requests = append(requests, hyperlinkRequest(sheetID, col, row, uri, text))
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: requests,
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to set hyperlink: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status": "success",
+		"links":  len(requests),
 	})
 }
+
+// hyperlinkRequest writes text into one cell with a run linking the whole text to uri
+func hyperlinkRequest(sheetID int64, col, row int, uri, text string) *sheets.Request {
+	return &sheets.Request{
+		UpdateCells: &sheets.UpdateCellsRequest{
+			Range: &sheets.GridRange{
+				SheetId:          sheetID,
+				StartRowIndex:    int64(row),
+				EndRowIndex:      int64(row + 1),
+				StartColumnIndex: int64(col),
+				EndColumnIndex:   int64(col + 1),
+			},
+			Rows: []*sheets.RowData{
+				{
+					Values: []*sheets.CellData{
+						{
+							UserEnteredValue: &sheets.ExtendedValue{StringValue: &text},
+							TextFormatRuns: []*sheets.TextFormatRun{
+								// StartIndex 0 is the zero value and has to be sent explicitly
+								{
+									StartIndex:      0,
+									Format:          &sheets.TextFormat{Link: &sheets.Link{Uri: uri}},
+									ForceSendFields: []string{"StartIndex"},
+								},
+							},
+						},
+					},
+				},
+			},
+			Fields: "userEnteredValue,textFormatRuns",
+		},
+	}
+}
@@ -0,0 +1,679 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+var (
+	deleteRowsWhereExpr   string
+	deleteRowsWhereHeader bool
+	deleteRowsWhereDryRun bool
+)
+
+var deleteRowsWhereCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete-rows-where <spreadsheet-id> <sheet-name>",
+		Short: "Delete rows matching a filter expression",
+		Long: `Delete rows matching a filter expression of the form <column> <operator> <value>.
+
+The column is a header name (when --header is set) or a column letter.
+Operators: ==, !=, >, >=, <, <=, contains, !contains.
+Values are compared as numbers, then dates (YYYY-MM-DD or M/D/YYYY), then text.
+Ordered operators never match empty cells or values of a different type.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runDeleteRowsWhere,
+	}
+	cmd.Flags().StringVar(&deleteRowsWhereExpr, "where", "", `Filter expression (e.g. 'status == "done"')`)
+	cmd.Flags().BoolVar(&deleteRowsWhereHeader, "header", true, "Treat the first row as a header (never deleted)")
+	cmd.Flags().BoolVar(&deleteRowsWhereDryRun, "dry-run", false, "List matching rows without deleting them")
+	cmd.MarkFlagRequired("where")
+	return cmd
+}()
+
+func runDeleteRowsWhere(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	filter, err := helpers.ParseFilter(deleteRowsWhereExpr)
+	if err != nil {
+		return err
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheet, err := helpers.GetSheetProperties(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	resp, err := service.Spreadsheets.Values.Get(spreadsheetID, helpers.SheetRange(sheet.Title, "")).
+		ValueRenderOption(ValueRenderUnformatted).
+		DateTimeRenderOption(DateRenderFormatted).Do()
+	if err != nil {
+		return fmt.Errorf("unable to get sheet data: %w", err)
+	}
+
+	firstDataRow := 0
+	var header []interface{}
+	if deleteRowsWhereHeader && len(resp.Values) > 0 {
+		header = resp.Values[0]
+		firstDataRow = 1
+	}
+
+	col, err := resolveFilterColumn(filter.Column, header)
+	if err != nil {
+		return err
+	}
+
+	var matches []map[string]interface{}
+	var rowIndexes []int
+	for i := firstDataRow; i < len(resp.Values); i++ {
+		row := resp.Values[i]
+		var cell interface{}
+		if col < len(row) {
+			cell = row[col]
+		}
+		if !filter.Match(cell) {
+			continue
+		}
+		rowIndexes = append(rowIndexes, i)
+		matches = append(matches, map[string]interface{}{
+			"row":    i + 1,
+			"values": row,
+		})
+	}
+
+	if deleteRowsWhereDryRun || len(rowIndexes) == 0 {
+		return helpers.PrintJSON(map[string]interface{}{
+			"status":  "success",
+			"dry_run": deleteRowsWhereDryRun,
+			"matched": len(matches),
+			"rows":    matches,
+		})
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: deleteRowRequests(sheet.SheetId, rowIndexes),
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to delete rows: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":  "success",
+		"deleted": len(rowIndexes),
+	})
+}
+
+// resolveFilterColumn maps a filter column to its index, preferring header names over column letters
+func resolveFilterColumn(column string, header []interface{}) (int, error) {
+	for i, name := range header {
+		if helpers.CellString(name) == column {
+			return i, nil
+		}
+	}
+
+	col, err := helpers.ColumnIndex(column)
+	if err != nil {
+		return 0, fmt.Errorf("column '%s' not found in header and is not a column letter", column)
+	}
+	return col, nil
+}
+
+// deleteRowRequests groups ascending 0-indexed rows into contiguous blocks and
+// returns DeleteDimension requests ordered bottom-up so earlier deletions never shift later ones
+func deleteRowRequests(sheetID int64, rowIndexes []int) []*sheets.Request {
+	var requests []*sheets.Request
+	end := len(rowIndexes) - 1
+	for end >= 0 {
+		start := end
+		for start > 0 && rowIndexes[start-1] == rowIndexes[start]-1 {
+			start--
+		}
+		requests = append(requests, &sheets.Request{
+			DeleteDimension: &sheets.DeleteDimensionRequest{
+				Range: &sheets.DimensionRange{
+					SheetId:    sheetID,
+					Dimension:  MajorDimensionRows,
+					StartIndex: int64(rowIndexes[start]),
+					EndIndex:   int64(rowIndexes[end] + 1),
+				},
+			},
+		})
+		end = start - 1
+	}
+	return requests
+}
+
+var insertDimensionInherit bool
+
+var insertRowsCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "insert-rows <spreadsheet-id> <sheet-name> <row> <count>",
+		Short: "Insert rows before a 1-based row number",
+		Args:  cobra.ExactArgs(4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInsertDimension(args, MajorDimensionRows)
+		},
+	}
+	cmd.Flags().BoolVar(&insertDimensionInherit, "inherit-from-before", false, "Copy formatting from the row above")
+	return cmd
+}()
+
+var insertColumnsCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "insert-columns <spreadsheet-id> <sheet-name> <column> <count>",
+		Short: "Insert columns before a column letter or 1-based number",
+		Args:  cobra.ExactArgs(4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInsertDimension(args, MajorDimensionColumns)
+		},
+	}
+	cmd.Flags().BoolVar(&insertDimensionInherit, "inherit-from-before", false, "Copy formatting from the column to the left")
+	return cmd
+}()
+
+func runInsertDimension(args []string, dimension string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	start, err := parseDimensionPosition(dimension, args[2])
+	if err != nil {
+		return err
+	}
+
+	count, err := strconv.ParseInt(args[3], 10, 64)
+	if err != nil || count <= 0 {
+		return fmt.Errorf("invalid count: %s", args[3])
+	}
+
+	if insertDimensionInherit && start == 0 {
+		return fmt.Errorf("--inherit-from-before cannot be used when inserting at the start of the sheet")
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.Request{
+		InsertDimension: &sheets.InsertDimensionRequest{
+			Range: &sheets.DimensionRange{
+				SheetId:    sheetID,
+				Dimension:  dimension,
+				StartIndex: start,
+				EndIndex:   start + count,
+			},
+			InheritFromBefore: insertDimensionInherit,
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to insert %s: %w", strings.ToLower(dimension), err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":    "success",
+		"dimension": dimension,
+		"position":  args[2],
+		"inserted":  count,
+	})
+}
+
+// parseDimensionPosition converts a 1-based row number, or a column letter / 1-based column number,
+// to a 0-based dimension index
+func parseDimensionPosition(dimension, value string) (int64, error) {
+	if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if number < 1 {
+			return 0, fmt.Errorf("invalid position %s: numbers start at 1", value)
+		}
+		return number - 1, nil
+	}
+
+	if dimension == MajorDimensionColumns {
+		col, err := helpers.ColumnIndex(value)
+		if err != nil {
+			return 0, err
+		}
+		return int64(col), nil
+	}
+
+	return 0, fmt.Errorf("invalid row number: %s", value)
+}
+
+var deleteRowsCmd = &cobra.Command{
+	Use:   "delete-rows <spreadsheet-id> <sheet-name> <start-row> <count>",
+	Short: "Delete rows starting at a 1-based row number",
+	Args:  cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDeleteDimension(args, MajorDimensionRows)
+	},
+}
+
+var deleteColumnsCmd = &cobra.Command{
+	Use:   "delete-columns <spreadsheet-id> <sheet-name> <start-column> <count|end-column>",
+	Short: "Delete columns by count (C 3) or inclusive letter range (C E)",
+	Args:  cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDeleteDimension(args, MajorDimensionColumns)
+	},
+}
+
+func runDeleteDimension(args []string, dimension string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	start, end, err := parseDimensionSpan(dimension, args[2], args[3])
+	if err != nil {
+		return err
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.Request{
+		DeleteDimension: &sheets.DeleteDimensionRequest{
+			Range: &sheets.DimensionRange{
+				SheetId:    sheetID,
+				Dimension:  dimension,
+				StartIndex: start,
+				EndIndex:   end,
+			},
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to delete %s: %w", strings.ToLower(dimension), err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":    "success",
+		"dimension": dimension,
+		"deleted":   end - start,
+	})
+}
+
+// parseDimensionSpan returns the 0-based half-open [start, end) span described by a start position
+// and either a count or, for columns, an inclusive end column letter
+func parseDimensionSpan(dimension, startValue, countOrEnd string) (int64, int64, error) {
+	start, err := parseDimensionPosition(dimension, startValue)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if count, err := strconv.ParseInt(countOrEnd, 10, 64); err == nil {
+		if count <= 0 {
+			return 0, 0, fmt.Errorf("invalid count: %s", countOrEnd)
+		}
+		return start, start + count, nil
+	}
+
+	if dimension != MajorDimensionColumns {
+		return 0, 0, fmt.Errorf("invalid count: %s", countOrEnd)
+	}
+
+	end, err := helpers.ColumnIndex(countOrEnd)
+	if err != nil {
+		return 0, 0, err
+	}
+	if int64(end) < start {
+		return 0, 0, fmt.Errorf("end column %s is before start column %s", countOrEnd, startValue)
+	}
+	return start, int64(end) + 1, nil
+}
+
+var autoResizeColumnsCmd = &cobra.Command{
+	Use:   "auto-resize-columns <spreadsheet-id> <sheet-name> [columns]",
+	Short: "Fit column widths to their content (e.g. A:D, C, or all columns)",
+	Args:  cobra.RangeArgs(2, 3),
+	RunE:  runAutoResizeColumns,
+}
+
+func runAutoResizeColumns(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	dimRange := &sheets.DimensionRange{
+		SheetId:   sheetID,
+		Dimension: MajorDimensionColumns,
+	}
+	columns := "all"
+	if len(args) > 2 {
+		columns = args[2]
+		dimRange, err = parseDimensionRange(sheetID, MajorDimensionColumns, columns)
+		if err != nil {
+			return err
+		}
+	}
+
+	req := &sheets.Request{
+		AutoResizeDimensions: &sheets.AutoResizeDimensionsRequest{
+			Dimensions: dimRange,
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to auto-resize columns: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]string{
+		"status":  "success",
+		"columns": columns,
+	})
+}
+
+// parseDimensionRange converts "C", "A:D" (columns) or "5", "2:10" (rows) into a DimensionRange
+func parseDimensionRange(sheetID int64, dimension, value string) (*sheets.DimensionRange, error) {
+	startValue, endValue, found := strings.Cut(value, ":")
+	if !found {
+		endValue = startValue
+	}
+
+	start, err := parseDimensionPosition(dimension, startValue)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseDimensionPosition(dimension, endValue)
+	if err != nil {
+		return nil, err
+	}
+	if end < start {
+		return nil, fmt.Errorf("invalid range %s: end is before start", value)
+	}
+
+	return &sheets.DimensionRange{
+		SheetId:    sheetID,
+		Dimension:  dimension,
+		StartIndex: start,
+		EndIndex:   end + 1,
+	}, nil
+}
+
+var setColumnWidthCmd = &cobra.Command{
+	Use:   "set-column-width <spreadsheet-id> <sheet-name> <columns> <pixels>",
+	Short: "Set the width of columns (e.g. A:D or C)",
+	Args:  cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSetDimensionSize(args, MajorDimensionColumns)
+	},
+}
+
+var setRowHeightCmd = &cobra.Command{
+	Use:   "set-row-height <spreadsheet-id> <sheet-name> <rows> <pixels>",
+	Short: "Set the height of rows (e.g. 2:10 or 1)",
+	Args:  cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSetDimensionSize(args, MajorDimensionRows)
+	},
+}
+
+func runSetDimensionSize(args []string, dimension string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	pixels, err := strconv.ParseInt(args[3], 10, 64)
+	if err != nil || pixels <= 0 {
+		return fmt.Errorf("invalid pixel size: %s", args[3])
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	dimRange, err := parseDimensionRange(sheetID, dimension, args[2])
+	if err != nil {
+		return err
+	}
+
+	req := dimensionSizeRequest(dimRange, pixels)
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to set %s size: %w", strings.ToLower(dimension), err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":    "success",
+		"dimension": dimension,
+		"range":     args[2],
+		"pixels":    pixels,
+	})
+}
+
+func dimensionSizeRequest(dimRange *sheets.DimensionRange, pixels int64) *sheets.Request {
+	return &sheets.Request{
+		UpdateDimensionProperties: &sheets.UpdateDimensionPropertiesRequest{
+			Range:      dimRange,
+			Properties: &sheets.DimensionProperties{PixelSize: pixels},
+			Fields:     "pixelSize",
+		},
+	}
+}
+
+var hideRowsCmd = &cobra.Command{
+	Use:   "hide-rows <spreadsheet-id> <sheet-name> <rows>",
+	Short: "Hide rows (e.g. 2:10 or 5)",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSetDimensionHidden(args, MajorDimensionRows, true)
+	},
+}
+
+var showRowsCmd = &cobra.Command{
+	Use:   "show-rows <spreadsheet-id> <sheet-name> <rows>",
+	Short: "Unhide rows (e.g. 2:10 or 5)",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSetDimensionHidden(args, MajorDimensionRows, false)
+	},
+}
+
+var hideColumnsCmd = &cobra.Command{
+	Use:   "hide-columns <spreadsheet-id> <sheet-name> <columns>",
+	Short: "Hide columns (e.g. A:D or C)",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSetDimensionHidden(args, MajorDimensionColumns, true)
+	},
+}
+
+var showColumnsCmd = &cobra.Command{
+	Use:   "show-columns <spreadsheet-id> <sheet-name> <columns>",
+	Short: "Unhide columns (e.g. A:D or C)",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runSetDimensionHidden(args, MajorDimensionColumns, false)
+	},
+}
+
+func runSetDimensionHidden(args []string, dimension string, hidden bool) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	dimRange, err := parseDimensionRange(sheetID, dimension, args[2])
+	if err != nil {
+		return err
+	}
+
+	req := dimensionHiddenRequest(dimRange, hidden)
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to update %s visibility: %w", strings.ToLower(dimension), err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":    "success",
+		"dimension": dimension,
+		"range":     args[2],
+		"hidden":    hidden,
+	})
+}
+
+func dimensionHiddenRequest(dimRange *sheets.DimensionRange, hidden bool) *sheets.Request {
+	return &sheets.Request{
+		UpdateDimensionProperties: &sheets.UpdateDimensionPropertiesRequest{
+			Range: dimRange,
+			Properties: &sheets.DimensionProperties{
+				HiddenByUser:    hidden,
+				ForceSendFields: []string{"HiddenByUser"},
+			},
+			Fields: "hiddenByUser",
+		},
+	}
+}
+
+var moveRowsCmd = &cobra.Command{
+	Use:   "move-rows <spreadsheet-id> <sheet-name> <rows> <before-row>",
+	Short: "Move a block of rows (e.g. 5:8) before another row",
+	Args:  cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMoveDimension(args, MajorDimensionRows)
+	},
+}
+
+var moveColumnsCmd = &cobra.Command{
+	Use:   "move-columns <spreadsheet-id> <sheet-name> <columns> <before-column>",
+	Short: "Move a block of columns (e.g. D:E) before another column",
+	Args:  cobra.ExactArgs(4),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMoveDimension(args, MajorDimensionColumns)
+	},
+}
+
+func runMoveDimension(args []string, dimension string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	// The destination is expressed in positions before the block is removed,
+	// which matches how MoveDimensionRequest interprets destinationIndex
+	destination, err := parseDimensionPosition(dimension, args[3])
+	if err != nil {
+		return err
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	source, err := parseDimensionRange(sheetID, dimension, args[2])
+	if err != nil {
+		return err
+	}
+
+	if destination >= source.StartIndex && destination <= source.EndIndex {
+		return fmt.Errorf("destination %s is inside or adjacent to the moved block %s", args[3], args[2])
+	}
+
+	req := &sheets.Request{
+		MoveDimension: &sheets.MoveDimensionRequest{
+			Source:           source,
+			DestinationIndex: destination,
+			ForceSendFields:  []string{"DestinationIndex"},
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to move %s: %w", strings.ToLower(dimension), err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":      "success",
+		"dimension":   dimension,
+		"source":      args[2],
+		"destination": args[3],
+	})
+}
@@ -0,0 +1,884 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+// shareRoles are the Drive permission roles accepted by share
+var shareRoles = map[string]bool{
+	"reader":    true,
+	"commenter": true,
+	"writer":    true,
+}
+
+var (
+	shareRole           string
+	shareAnyoneWithLink bool
+	shareNotify         bool
+	shareMessage        string
+)
+
+var shareCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "share <spreadsheet-id> [email]",
+		Short: "Share a spreadsheet with a user or with anyone who has the link",
+		Long: `Share a spreadsheet with a user or with anyone who has the link.
+
+Give an email to grant access to a user (a notification email is sent unless
+--notify=false), or --anyone-with-link to make the link work for everyone.`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: runShare,
+	}
+	cmd.Flags().StringVar(&shareRole, "role", "reader", "Role: reader, commenter or writer")
+	cmd.Flags().BoolVar(&shareAnyoneWithLink, "anyone-with-link", false, "Grant the role to anyone with the link instead of a user")
+	cmd.Flags().BoolVar(&shareNotify, "notify", true, "Send a notification email to the user")
+	cmd.Flags().StringVar(&shareMessage, "message", "", "Custom message included in the notification email")
+	return cmd
+}()
+
+func runShare(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	if !shareRoles[shareRole] {
+		return fmt.Errorf("invalid role '%s': expected reader, commenter or writer", shareRole)
+	}
+
+	permission := &drive.Permission{Role: shareRole}
+	switch {
+	case shareAnyoneWithLink && len(args) == 2:
+		return fmt.Errorf("provide either an email or --anyone-with-link, not both")
+	case shareAnyoneWithLink:
+		permission.Type = "anyone"
+	case len(args) == 2:
+		permission.Type = "user"
+		permission.EmailAddress = args[1]
+	default:
+		return fmt.Errorf("an email or --anyone-with-link is required")
+	}
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	call := driveService.Permissions.Create(spreadsheetID, permission).
+		Fields("id,type,role,emailAddress")
+	if permission.Type == "user" {
+		call = call.SendNotificationEmail(shareNotify)
+		if shareMessage != "" {
+			call = call.EmailMessage(shareMessage)
+		}
+	}
+
+	result, err := call.Do()
+	if err != nil {
+		return fmt.Errorf("unable to share spreadsheet: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":        "success",
+		"permission_id": result.Id,
+		"type":          result.Type,
+		"role":          result.Role,
+		"email":         result.EmailAddress,
+	})
+}
+
+var listPermissionsCmd = &cobra.Command{
+	Use:   "list-permissions <spreadsheet-id>",
+	Short: "List who has access to a spreadsheet",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runListPermissions,
+}
+
+func runListPermissions(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	permissions, err := listPermissions(driveService, spreadsheetID)
+	if err != nil {
+		return err
+	}
+
+	result := make([]map[string]interface{}, 0, len(permissions))
+	for _, permission := range permissions {
+		entry := map[string]interface{}{
+			"id":   permission.Id,
+			"type": permission.Type,
+			"role": permission.Role,
+		}
+		if permission.EmailAddress != "" {
+			entry["email"] = permission.EmailAddress
+		}
+		if permission.DisplayName != "" {
+			entry["name"] = permission.DisplayName
+		}
+		if permission.Domain != "" {
+			entry["domain"] = permission.Domain
+		}
+		if permission.PendingOwner {
+			entry["pending_owner"] = true
+		}
+		result = append(result, entry)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":      "success",
+		"count":       len(result),
+		"permissions": result,
+	})
+}
+
+// listPermissions returns every permission of a file, following pagination
+func listPermissions(driveService *drive.Service, fileID string) ([]*drive.Permission, error) {
+	var permissions []*drive.Permission
+	err := driveService.Permissions.List(fileID).
+		Fields("nextPageToken,permissions(id,type,role,emailAddress,displayName,domain,pendingOwner)").
+		Pages(context.Background(), func(page *drive.PermissionList) error {
+			permissions = append(permissions, page.Permissions...)
+			return nil
+		})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list permissions: %w", err)
+	}
+	return permissions, nil
+}
+
+var removePermissionCmd = &cobra.Command{
+	Use:   "remove-permission <spreadsheet-id> <email|permission-id>",
+	Short: "Revoke access by email address or permission ID",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runRemovePermission,
+}
+
+func runRemovePermission(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	target := args[1]
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	permissionID := target
+	if strings.Contains(target, "@") {
+		permissionID, err = findPermissionID(driveService, spreadsheetID, target)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := driveService.Permissions.Delete(spreadsheetID, permissionID).Do(); err != nil {
+		return fmt.Errorf("unable to remove permission: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]string{
+		"status":        "success",
+		"permission_id": permissionID,
+	})
+}
+
+// findPermissionID returns the ID of the permission granted to an email address
+func findPermissionID(driveService *drive.Service, fileID, email string) (string, error) {
+	permissions, err := listPermissions(driveService, fileID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, permission := range permissions {
+		if strings.EqualFold(permission.EmailAddress, email) {
+			return permission.Id, nil
+		}
+	}
+	return "", fmt.Errorf("no permission found for '%s'", email)
+}
+
+var (
+	deletePermanent bool
+	deleteForce     bool
+)
+
+var deleteCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete <spreadsheet-id>",
+		Short: "Move a spreadsheet to the Drive trash, or delete it permanently",
+		Long: `Move a spreadsheet to the Drive trash (restore it with "restore").
+
+With --permanent the file is deleted for good after a confirmation prompt,
+which --force skips.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runDelete,
+	}
+	cmd.Flags().BoolVar(&deletePermanent, "permanent", false, "Delete permanently instead of trashing")
+	cmd.Flags().BoolVar(&deleteForce, "force", false, "Skip the confirmation prompt of --permanent")
+	return cmd
+}()
+
+func runDelete(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	file, err := driveService.Files.Get(spreadsheetID).Fields("id,name").Do()
+	if err != nil {
+		return fmt.Errorf("unable to get spreadsheet: %w", err)
+	}
+
+	if deletePermanent {
+		if !deleteForce && !helpers.Confirm(fmt.Sprintf("Permanently delete '%s'? This cannot be undone.", file.Name)) {
+			return fmt.Errorf("deletion of '%s' aborted", file.Name)
+		}
+		if err := driveService.Files.Delete(spreadsheetID).Do(); err != nil {
+			return fmt.Errorf("unable to delete spreadsheet: %w", err)
+		}
+	} else {
+		_, err := driveService.Files.Update(spreadsheetID, &drive.File{Trashed: true}).Fields("id").Do()
+		if err != nil {
+			return fmt.Errorf("unable to trash spreadsheet: %w", err)
+		}
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":    "success",
+		"id":        spreadsheetID,
+		"name":      file.Name,
+		"permanent": deletePermanent,
+	})
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <spreadsheet-id>",
+	Short: "Restore a spreadsheet from the Drive trash",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runRestore,
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Trashed is false by default, so it has to be sent explicitly
+	file, err := driveService.Files.Update(spreadsheetID, &drive.File{
+		Trashed:         false,
+		ForceSendFields: []string{"Trashed"},
+	}).Fields("id,name").Do()
+	if err != nil {
+		return fmt.Errorf("unable to restore spreadsheet: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]string{
+		"status": "success",
+		"id":     file.Id,
+		"name":   file.Name,
+		"url":    fmt.Sprintf(GoogleSheetsURLPattern, file.Id),
+	})
+}
+
+var (
+	listFolderID string
+	listLimit    int
+)
+
+var listCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the spreadsheets visible in Drive, most recently modified first",
+		Args:  cobra.NoArgs,
+		RunE:  runList,
+	}
+	cmd.Flags().StringVar(&listFolderID, "folder", "", "Only list spreadsheets in this folder")
+	cmd.Flags().IntVar(&listLimit, "limit", 0, "Maximum number of spreadsheets (default: all)")
+	return cmd
+}()
+
+func runList(cmd *cobra.Command, args []string) error {
+	query := ""
+	if listFolderID != "" {
+		query = fmt.Sprintf("'%s' in parents", driveQueryEscape(listFolderID))
+	}
+	return printSpreadsheets(query, listLimit)
+}
+
+var (
+	searchSpreadsheetsFullText bool
+	searchSpreadsheetsLimit    int
+)
+
+var searchSpreadsheetsCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search-spreadsheets <query>",
+		Short: "Find spreadsheets in Drive whose name contains a text",
+		Long: `Find spreadsheets in Drive whose name contains a text (case-insensitive).
+
+With --full-text, the content of the spreadsheets is searched as well.
+To search the cells of one spreadsheet, use "search".`,
+		Args: cobra.ExactArgs(1),
+		RunE: runSearchSpreadsheets,
+	}
+	cmd.Flags().BoolVar(&searchSpreadsheetsFullText, "full-text", false, "Search the content too, not just the name")
+	cmd.Flags().IntVar(&searchSpreadsheetsLimit, "limit", 0, "Maximum number of spreadsheets (default: all)")
+	return cmd
+}()
+
+func runSearchSpreadsheets(cmd *cobra.Command, args []string) error {
+	field := "name"
+	if searchSpreadsheetsFullText {
+		field = "fullText"
+	}
+	query := fmt.Sprintf("%s contains '%s'", field, driveQueryEscape(args[0]))
+	return printSpreadsheets(query, searchSpreadsheetsLimit)
+}
+
+// printSpreadsheets lists the non-trashed spreadsheets matching a Drive query (empty for all) and prints them
+func printSpreadsheets(query string, limit int) error {
+	ctx := context.Background()
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	q := fmt.Sprintf("mimeType = '%s' and trashed = false", MimeTypeGoogleSheets)
+	if query != "" {
+		q += " and " + query
+	}
+
+	result := []map[string]interface{}{}
+	call := driveService.Files.List().Q(q).
+		OrderBy("modifiedTime desc").
+		Fields("nextPageToken,files(id,name,modifiedTime,owners(emailAddress))")
+	if limit > 0 {
+		call.PageSize(int64(min(limit, 1000)))
+	}
+
+	pageToken := ""
+	for {
+		if pageToken != "" {
+			call.PageToken(pageToken)
+		}
+		resp, err := call.Do()
+		if err != nil {
+			return fmt.Errorf("unable to list spreadsheets: %w", err)
+		}
+
+		for _, file := range resp.Files {
+			owners := make([]string, 0, len(file.Owners))
+			for _, owner := range file.Owners {
+				owners = append(owners, owner.EmailAddress)
+			}
+			result = append(result, map[string]interface{}{
+				"id":       file.Id,
+				"name":     file.Name,
+				"modified": file.ModifiedTime,
+				"owners":   owners,
+				"url":      fmt.Sprintf(GoogleSheetsURLPattern, file.Id),
+			})
+			if limit > 0 && len(result) == limit {
+				break
+			}
+		}
+
+		pageToken = resp.NextPageToken
+		if pageToken == "" || (limit > 0 && len(result) == limit) {
+			break
+		}
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":       "success",
+		"count":        len(result),
+		"spreadsheets": result,
+	})
+}
+
+// driveQueryEscape escapes a value for use inside a quoted Drive query string
+func driveQueryEscape(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return strings.ReplaceAll(value, `'`, `\'`)
+}
+
+var renameCmd = &cobra.Command{
+	Use:   "rename <spreadsheet-id> <new-title>",
+	Short: "Rename a spreadsheet",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runRename,
+}
+
+func runRename(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	title := args[1]
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := driveService.Files.Update(spreadsheetID, &drive.File{Name: title}).Fields("id").Do(); err != nil {
+		return fmt.Errorf("unable to rename spreadsheet: %w", err)
+	}
+	// The title changed through Drive, out of sight of the Sheets cache
+	helpers.InvalidateSheetCache(spreadsheetID)
+
+	return helpers.PrintJSON(map[string]string{
+		"status": "success",
+		"id":     spreadsheetID,
+		"name":   title,
+	})
+}
+
+var moveCmd = &cobra.Command{
+	Use:   "move <spreadsheet-id> <folder-id>",
+	Short: "Move a spreadsheet to another Drive folder",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runMove,
+}
+
+func runMove(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	folderID := args[1]
+
+	if err := moveToFolder(ctx, spreadsheetID, folderID); err != nil {
+		return fmt.Errorf("unable to move spreadsheet: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]string{
+		"status": "success",
+		"id":     spreadsheetID,
+		"folder": folderID,
+	})
+}
+
+var (
+	copyFolderID      string
+	copyStructureOnly bool
+)
+
+var copyCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "copy <spreadsheet-id> <new-title>",
+		Short: "Copy a whole spreadsheet",
+		Long: `Copy a whole spreadsheet with Drive.
+
+With --structure-only, every cell value and formula of the copy is cleared
+while sheets, formatting, validation, notes and charts are kept.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runCopy,
+	}
+	cmd.Flags().StringVar(&copyFolderID, "folder", "", "Folder ID to create the copy in")
+	cmd.Flags().BoolVar(&copyStructureOnly, "structure-only", false, "Clear the values of the copy, keeping formatting")
+	return cmd
+}()
+
+func runCopy(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	title := args[1]
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	copied, err := copySpreadsheet(driveService, spreadsheetID, title, copyFolderID)
+	if err != nil {
+		return fmt.Errorf("unable to copy spreadsheet: %w", err)
+	}
+
+	if copyStructureOnly {
+		if err := clearAllValues(ctx, copied.Id); err != nil {
+			return fmt.Errorf("copy %s created but its values could not be cleared: %w", copied.Id, err)
+		}
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":         "success",
+		"id":             copied.Id,
+		"name":           copied.Name,
+		"url":            fmt.Sprintf(GoogleSheetsURLPattern, copied.Id),
+		"structure_only": copyStructureOnly,
+	})
+}
+
+// clearAllValues removes the values and formulas of every sheet, leaving formats untouched
+func clearAllValues(ctx context.Context, spreadsheetID string) error {
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	spreadsheet, err := helpers.GetSpreadsheetSheets(service, spreadsheetID)
+	if err != nil {
+		return err
+	}
+
+	var requests []*sheets.Request
+	for _, sheet := range spreadsheet.Sheets {
+		if sheet.Properties.SheetType != "" && sheet.Properties.SheetType != "GRID" {
+			continue
+		}
+		requests = append(requests, &sheets.Request{
+			UpdateCells: &sheets.UpdateCellsRequest{
+				Range:  &sheets.GridRange{SheetId: sheet.Properties.SheetId},
+				Fields: "userEnteredValue",
+			},
+		})
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: requests,
+	}
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	return err
+}
+
+var (
+	linkSharingAnyone     string
+	linkSharingDomain     string
+	linkSharingDomainRole string
+)
+
+var linkSharingCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "link-sharing <spreadsheet-id>",
+		Short: "Show or change who can open a spreadsheet through its link",
+		Long: `Show or change who can open a spreadsheet through its link.
+
+--anyone sets the role of anyone with the link, --domain with --domain-role
+the role of everyone in a Google Workspace domain. Use "none" to turn link
+access off. Without flags, the current link settings are printed.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runLinkSharing,
+	}
+	cmd.Flags().StringVar(&linkSharingAnyone, "anyone", "", "Role for anyone with the link: reader, commenter, writer or none")
+	cmd.Flags().StringVar(&linkSharingDomain, "domain", "", "Domain for --domain-role (e.g. example.com)")
+	cmd.Flags().StringVar(&linkSharingDomainRole, "domain-role", "reader", "Role for the --domain users: reader, commenter, writer or none")
+	return cmd
+}()
+
+func runLinkSharing(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	for _, role := range []string{linkSharingAnyone, linkSharingDomainRole} {
+		if role != "" && role != "none" && !shareRoles[role] {
+			return fmt.Errorf("invalid role '%s': expected reader, commenter, writer or none", role)
+		}
+	}
+	if cmd.Flags().Changed("domain-role") && linkSharingDomain == "" {
+		return fmt.Errorf("--domain-role requires --domain")
+	}
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	if linkSharingAnyone != "" {
+		if err := setLinkPermission(driveService, spreadsheetID, "anyone", "", linkSharingAnyone); err != nil {
+			return err
+		}
+	}
+	if linkSharingDomain != "" {
+		if err := setLinkPermission(driveService, spreadsheetID, "domain", linkSharingDomain, linkSharingDomainRole); err != nil {
+			return err
+		}
+	}
+
+	permissions, err := listPermissions(driveService, spreadsheetID)
+	if err != nil {
+		return err
+	}
+
+	links := []map[string]string{}
+	for _, permission := range permissions {
+		switch permission.Type {
+		case "anyone":
+			links = append(links, map[string]string{"type": "anyone", "role": permission.Role})
+		case "domain":
+			links = append(links, map[string]string{"type": "domain", "domain": permission.Domain, "role": permission.Role})
+		}
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status": "success",
+		"url":    fmt.Sprintf(GoogleSheetsURLPattern, spreadsheetID),
+		"links":  links,
+	})
+}
+
+// setLinkPermission creates, updates or (role "none") deletes the anyone or domain permission of a file
+func setLinkPermission(driveService *drive.Service, fileID, permissionType, domain, role string) error {
+	permissions, err := listPermissions(driveService, fileID)
+	if err != nil {
+		return err
+	}
+
+	var existing *drive.Permission
+	for _, permission := range permissions {
+		if permission.Type == permissionType && strings.EqualFold(permission.Domain, domain) {
+			existing = permission
+			break
+		}
+	}
+
+	switch {
+	case role == "none" && existing == nil:
+		return nil
+	case role == "none":
+		err = driveService.Permissions.Delete(fileID, existing.Id).Do()
+	case existing == nil:
+		// Link access only: the file does not show up in searches of the domain
+		_, err = driveService.Permissions.Create(fileID, &drive.Permission{
+			Type:               permissionType,
+			Domain:             domain,
+			Role:               role,
+			AllowFileDiscovery: false,
+		}).Fields("id").Do()
+	default:
+		_, err = driveService.Permissions.Update(fileID, existing.Id, &drive.Permission{Role: role}).Fields("id").Do()
+	}
+	if err != nil {
+		return fmt.Errorf("unable to set %s link sharing: %w", permissionType, err)
+	}
+	return nil
+}
+
+var publishOutsideDomain bool
+
+var publishCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "publish <spreadsheet-id>",
+		Short: "Publish a spreadsheet to the web, republishing automatically on change",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runPublish,
+	}
+	cmd.Flags().BoolVar(&publishOutsideDomain, "outside-domain", true, "Make the published version visible outside the Workspace domain")
+	return cmd
+}()
+
+func runPublish(cmd *cobra.Command, args []string) error {
+	return setPublished(args[0], &drive.Revision{
+		Published:              true,
+		PublishAuto:            true,
+		PublishedOutsideDomain: publishOutsideDomain,
+		ForceSendFields:        []string{"PublishedOutsideDomain"},
+	})
+}
+
+var unpublishCmd = &cobra.Command{
+	Use:   "unpublish <spreadsheet-id>",
+	Short: "Stop publishing a spreadsheet to the web",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runUnpublish,
+}
+
+func runUnpublish(cmd *cobra.Command, args []string) error {
+	return setPublished(args[0], &drive.Revision{
+		Published:       false,
+		PublishAuto:     false,
+		ForceSendFields: []string{"Published", "PublishAuto"},
+	})
+}
+
+// setPublished applies publishing settings to the latest revision, which is what Sheets publishes
+func setPublished(spreadsheetID string, settings *drive.Revision) error {
+	ctx := context.Background()
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	// headRevisionId is only set for binary files, so take the last listed revision
+	revisionID := ""
+	err = driveService.Revisions.List(spreadsheetID).Fields("nextPageToken,revisions(id)").
+		Pages(ctx, func(page *drive.RevisionList) error {
+			if len(page.Revisions) > 0 {
+				revisionID = page.Revisions[len(page.Revisions)-1].Id
+			}
+			return nil
+		})
+	if err != nil {
+		return fmt.Errorf("unable to list revisions: %w", err)
+	}
+	if revisionID == "" {
+		return fmt.Errorf("spreadsheet %s has no revision to publish", spreadsheetID)
+	}
+
+	result, err := driveService.Revisions.Update(spreadsheetID, revisionID, settings).
+		Fields("id,published,publishAuto,publishedOutsideDomain").Do()
+	if err != nil {
+		return fmt.Errorf("unable to update publishing settings: %w", err)
+	}
+
+	output := map[string]interface{}{
+		"status":       "success",
+		"revision_id":  result.Id,
+		"published":    result.Published,
+		"publish_auto": result.PublishAuto,
+	}
+	if result.Published {
+		output["outside_domain"] = result.PublishedOutsideDomain
+		output["published_url"] = fmt.Sprintf(GoogleSheetsPublishURLPattern, spreadsheetID)
+	}
+	return helpers.PrintJSON(output)
+}
+
+var transferOwnershipPending bool
+
+var transferOwnershipCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "transfer-ownership <spreadsheet-id> <email>",
+		Short: "Make another user the owner of a spreadsheet",
+		Long: `Make another user the owner of a spreadsheet.
+
+Within a Google Workspace domain the transfer is immediate and the current
+owner becomes a writer. Consumer (gmail.com) accounts must accept ownership:
+the user is made a writer flagged as pending owner and gets an email to
+accept. This pending flow is used automatically when Drive asks for consent,
+or explicitly with --pending.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runTransferOwnership,
+	}
+	cmd.Flags().BoolVar(&transferOwnershipPending, "pending", false, "Request a transfer the new owner has to accept")
+	return cmd
+}()
+
+func runTransferOwnership(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	email := args[1]
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	permissions, err := listPermissions(driveService, spreadsheetID)
+	if err != nil {
+		return err
+	}
+
+	var existing *drive.Permission
+	for _, permission := range permissions {
+		if permission.Type == "user" && strings.EqualFold(permission.EmailAddress, email) {
+			existing = permission
+			break
+		}
+	}
+	if existing != nil && existing.Role == "owner" {
+		return fmt.Errorf("'%s' already owns this spreadsheet", email)
+	}
+
+	if !transferOwnershipPending {
+		err = transferOwnership(driveService, spreadsheetID, email, existing)
+		if err == nil {
+			return helpers.PrintJSON(map[string]string{
+				"status": "success",
+				"id":     spreadsheetID,
+				"owner":  email,
+			})
+		}
+		if !isConsentRequired(err) {
+			return fmt.Errorf("unable to transfer ownership: %w", err)
+		}
+	}
+
+	if err := requestPendingOwner(driveService, spreadsheetID, email, existing); err != nil {
+		return fmt.Errorf("unable to request ownership transfer: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]string{
+		"status":        "pending",
+		"id":            spreadsheetID,
+		"pending_owner": email,
+		"message":       "the new owner must accept the transfer from the email or the Drive sharing dialog",
+	})
+}
+
+// transferOwnership gives the owner role to email, which Drive only allows with transferOwnership set
+func transferOwnership(driveService *drive.Service, fileID, email string, existing *drive.Permission) error {
+	if existing != nil {
+		_, err := driveService.Permissions.Update(fileID, existing.Id, &drive.Permission{Role: "owner"}).
+			TransferOwnership(true).Fields("id").Do()
+		return err
+	}
+
+	_, err := driveService.Permissions.Create(fileID, &drive.Permission{
+		Type:         "user",
+		Role:         "owner",
+		EmailAddress: email,
+	}).TransferOwnership(true).Fields("id").Do()
+	return err
+}
+
+// requestPendingOwner makes email a writer flagged as pending owner, the consumer-account transfer flow
+func requestPendingOwner(driveService *drive.Service, fileID, email string, existing *drive.Permission) error {
+	var permissionID string
+	if existing != nil {
+		permissionID = existing.Id
+	} else {
+		created, err := driveService.Permissions.Create(fileID, &drive.Permission{
+			Type:         "user",
+			Role:         "writer",
+			EmailAddress: email,
+		}).Fields("id").Do()
+		if err != nil {
+			return err
+		}
+		permissionID = created.Id
+	}
+
+	// Only writers can be pending owners, so readers and commenters are promoted in the same update
+	_, err := driveService.Permissions.Update(fileID, permissionID, &drive.Permission{
+		Role:         "writer",
+		PendingOwner: true,
+	}).Fields("id").Do()
+	return err
+}
+
+// isConsentRequired reports whether Drive refused an ownership transfer that the recipient has to accept
+func isConsentRequired(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, item := range apiErr.Errors {
+		if item.Reason == "consentRequiredForOwnershipTransfer" {
+			return true
+		}
+	}
+	return false
+}
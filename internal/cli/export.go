@@ -0,0 +1,300 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+var exportChartCmd = &cobra.Command{
+	Use:   "export-chart <spreadsheet-id> <chart-id> <output.png>",
+	Short: "Render an embedded chart to a PNG image",
+	Args:  cobra.ExactArgs(3),
+	RunE:  runExportChart,
+}
+
+func runExportChart(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	outputPath := args[2]
+
+	chartID, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid chart ID: %s", args[1])
+	}
+
+	url := fmt.Sprintf(GoogleSheetsChartImageURLPattern, spreadsheetID, chartID, chartID)
+	size, err := downloadExport(ctx, url, outputPath, "image/")
+	if err != nil {
+		return fmt.Errorf("unable to export chart %d: %w", chartID, err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":   "success",
+		"chart_id": chartID,
+		"file":     outputPath,
+		"bytes":    size,
+	})
+}
+
+// downloadExport fetches an export URL with the authenticated client and writes the body to path.
+// The response Content-Type must start with wantType, otherwise an HTML error page was returned.
+func downloadExport(ctx context.Context, url, path, wantType string) (int64, error) {
+	client, err := auth.GetClient(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("export returned HTTP %d", resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, wantType) {
+		return 0, fmt.Errorf("unexpected export content type %q", contentType)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("unable to create file: %w", err)
+	}
+	defer file.Close()
+
+	size, err := io.Copy(file, resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("unable to write file: %w", err)
+	}
+
+	return size, nil
+}
+
+var exportXLSXSheet string
+
+var exportXLSXCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-xlsx <spreadsheet-id> <output.xlsx>",
+		Short: "Export the workbook (values and formatting) to an Excel file",
+		Long: `Export the workbook (values and formatting) to an Excel file with Drive export.
+
+With --sheet, a temporary copy holding only that sheet is exported and then
+deleted. Formulas referencing other sheets become #REF! in that case.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runExportXLSX,
+	}
+	cmd.Flags().StringVar(&exportXLSXSheet, "sheet", "", "Export a single sheet only")
+	return cmd
+}()
+
+func runExportXLSX(cmd *cobra.Command, args []string) error {
+	return exportWorkbook(args[0], args[1], exportXLSXSheet, MimeTypeXLSX)
+}
+
+var exportODSSheet string
+
+var exportODSCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-ods <spreadsheet-id> <output.ods>",
+		Short: "Export the workbook to an OpenDocument spreadsheet",
+		Long: `Export the workbook to an OpenDocument spreadsheet with Drive export.
+
+With --sheet, a temporary copy holding only that sheet is exported and then
+deleted. Formulas referencing other sheets become #REF! in that case.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runExportODS,
+	}
+	cmd.Flags().StringVar(&exportODSSheet, "sheet", "", "Export a single sheet only")
+	return cmd
+}()
+
+func runExportODS(cmd *cobra.Command, args []string) error {
+	return exportWorkbook(args[0], args[1], exportODSSheet, MimeTypeODS)
+}
+
+// exportWorkbook exports a spreadsheet with Drive Files.Export and prints the result.
+// A non-empty sheetRef exports a temporary single-sheet copy instead.
+func exportWorkbook(spreadsheetID, outputPath, sheetRef, mimeType string) error {
+	ctx := context.Background()
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return err
+	}
+
+	fileID := spreadsheetID
+	if sheetRef != "" {
+		copyID, err := singleSheetCopy(ctx, driveService, spreadsheetID, sheetRef)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := driveService.Files.Delete(copyID).Do(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: unable to delete temporary copy %s: %v\n", copyID, err)
+			}
+		}()
+		fileID = copyID
+	}
+
+	resp, err := driveService.Files.Export(fileID, mimeType).Download()
+	if err != nil {
+		return fmt.Errorf("unable to export spreadsheet: %w", err)
+	}
+	defer resp.Body.Close()
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("unable to create file: %w", err)
+	}
+	defer file.Close()
+
+	size, err := io.Copy(file, resp.Body)
+	if err != nil {
+		return fmt.Errorf("unable to write file: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status": "success",
+		"file":   outputPath,
+		"bytes":  size,
+	})
+}
+
+// singleSheetCopy copies a spreadsheet with Drive and deletes every sheet but sheetRef from the copy
+func singleSheetCopy(ctx context.Context, driveService *drive.Service, spreadsheetID, sheetRef string) (string, error) {
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	spreadsheet, err := helpers.GetSpreadsheetSheets(service, spreadsheetID)
+	if err != nil {
+		return "", fmt.Errorf("unable to get spreadsheet: %w", err)
+	}
+
+	keep, err := helpers.FindSheet(spreadsheet, sheetRef)
+	if err != nil {
+		return "", err
+	}
+
+	copied, err := driveService.Files.Copy(spreadsheetID, &drive.File{
+		Name: spreadsheet.Properties.Title + " - " + keep.Title,
+	}).Fields("id").Do()
+	if err != nil {
+		return "", fmt.Errorf("unable to copy spreadsheet: %w", err)
+	}
+
+	// Sheet IDs are preserved by Drive copies
+	var requests []*sheets.Request
+	for _, sheet := range spreadsheet.Sheets {
+		if sheet.Properties.SheetId == keep.SheetId {
+			continue
+		}
+		requests = append(requests, &sheets.Request{
+			DeleteSheet: &sheets.DeleteSheetRequest{SheetId: sheet.Properties.SheetId},
+		})
+	}
+
+	if len(requests) > 0 {
+		batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+			Requests: requests,
+		}
+		if _, err := service.Spreadsheets.BatchUpdate(copied.Id, batchReq).Do(); err != nil {
+			_ = driveService.Files.Delete(copied.Id).Do()
+			return "", fmt.Errorf("unable to trim temporary copy: %w", err)
+		}
+	}
+
+	return copied.Id, nil
+}
+
+var (
+	exportPDFSheet        string
+	exportPDFRange        string
+	exportPDFSize         string
+	exportPDFLandscape    bool
+	exportPDFFitWidth     bool
+	exportPDFGridlines    bool
+	exportPDFRepeatHeader bool
+)
+
+var exportPDFCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-pdf <spreadsheet-id> <output.pdf>",
+		Short: "Export the workbook, a sheet or a range to PDF",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runExportPDF,
+	}
+	cmd.Flags().StringVar(&exportPDFSheet, "sheet", "", "Export a single sheet only")
+	cmd.Flags().StringVar(&exportPDFRange, "range", "", "Export a range of --sheet (e.g. A1:H40)")
+	cmd.Flags().StringVar(&exportPDFSize, "size", "A4", "Page size (A3, A4, A5, letter, legal, tabloid...)")
+	cmd.Flags().BoolVar(&exportPDFLandscape, "landscape", false, "Landscape orientation")
+	cmd.Flags().BoolVar(&exportPDFFitWidth, "fit-width", true, "Scale content to the page width")
+	cmd.Flags().BoolVar(&exportPDFGridlines, "gridlines", false, "Print gridlines")
+	cmd.Flags().BoolVar(&exportPDFRepeatHeader, "repeat-header", false, "Repeat frozen rows on every page")
+	return cmd
+}()
+
+func runExportPDF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	outputPath := args[1]
+
+	if exportPDFRange != "" && exportPDFSheet == "" {
+		return fmt.Errorf("--range requires --sheet")
+	}
+
+	params := url.Values{}
+	params.Set("format", "pdf")
+	params.Set("size", exportPDFSize)
+	params.Set("portrait", strconv.FormatBool(!exportPDFLandscape))
+	params.Set("fitw", strconv.FormatBool(exportPDFFitWidth))
+	params.Set("gridlines", strconv.FormatBool(exportPDFGridlines))
+	params.Set("fzr", strconv.FormatBool(exportPDFRepeatHeader))
+
+	if exportPDFSheet != "" {
+		service, err := auth.GetSheetsService(ctx)
+		if err != nil {
+			return err
+		}
+		sheetID, err := helpers.GetSheetID(service, spreadsheetID, exportPDFSheet)
+		if err != nil {
+			return err
+		}
+		params.Set("gid", strconv.FormatInt(sheetID, 10))
+		if exportPDFRange != "" {
+			params.Set("range", exportPDFRange)
+		}
+	}
+
+	exportURL := fmt.Sprintf(GoogleSheetsExportURLPattern, spreadsheetID) + "?" + params.Encode()
+	size, err := downloadExport(ctx, exportURL, outputPath, "application/pdf")
+	if err != nil {
+		return fmt.Errorf("unable to export PDF: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status": "success",
+		"file":   outputPath,
+		"bytes":  size,
+	})
+}
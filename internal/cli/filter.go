@@ -0,0 +1,466 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+var (
+	setFilterHideValues []string
+	setFilterConditions []string
+)
+
+var setFilterCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-filter <spreadsheet-id> <sheet-name> <range>",
+		Short: "Set the basic filter of a sheet on a range",
+		Long: `Set the basic filter of a sheet on a range, replacing any existing one.
+
+Per-column criteria use column letters of the sheet:
+  --hide-values "B=Closed,Cancelled"   hide rows whose B value is listed
+  --condition "C:>:100"                show rows where C > 100
+  --condition "D:between:1:10"         condition values are separated by ':'
+
+Conditions accept the same shorthands as conditional-format (>, contains, empty...)
+or any API condition type.`,
+		Args: cobra.ExactArgs(3),
+		RunE: runSetFilter,
+	}
+	cmd.Flags().StringArrayVar(&setFilterHideValues, "hide-values", nil, "COLUMN=value1,value2 of values to hide (repeatable)")
+	cmd.Flags().StringArrayVar(&setFilterConditions, "condition", nil, "COLUMN:CONDITION[:VALUE...] rows must match (repeatable)")
+	return cmd
+}()
+
+func runSetFilter(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	rangeA1 := args[2]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	gridRange, err := helpers.ParseGridRange(sheetID, rangeA1)
+	if err != nil {
+		return err
+	}
+
+	filterSpecs, err := buildFilterSpecs(setFilterHideValues, setFilterConditions)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.Request{
+		SetBasicFilter: &sheets.SetBasicFilterRequest{
+			Filter: &sheets.BasicFilter{
+				Range:       gridRange,
+				FilterSpecs: filterSpecs,
+			},
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to set basic filter: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":   "success",
+		"range":    rangeA1,
+		"criteria": len(filterSpecs),
+	})
+}
+
+// buildFilterSpecs turns --hide-values and --condition flags into per-column filter specs
+func buildFilterSpecs(hideValues, conditions []string) ([]*sheets.FilterSpec, error) {
+	criteria := map[int64]*sheets.FilterCriteria{}
+	criteriaFor := func(column string) (*sheets.FilterCriteria, error) {
+		col, err := helpers.ColumnIndex(strings.TrimSpace(column))
+		if err != nil {
+			return nil, err
+		}
+		if criteria[int64(col)] == nil {
+			criteria[int64(col)] = &sheets.FilterCriteria{}
+		}
+		return criteria[int64(col)], nil
+	}
+
+	for _, spec := range hideValues {
+		column, values, ok := strings.Cut(spec, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --hide-values '%s': expected COLUMN=value1,value2", spec)
+		}
+		c, err := criteriaFor(column)
+		if err != nil {
+			return nil, err
+		}
+		c.HiddenValues = append(c.HiddenValues, strings.Split(values, ",")...)
+	}
+
+	for _, spec := range conditions {
+		parts := strings.Split(spec, ":")
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("invalid --condition '%s': expected COLUMN:CONDITION[:VALUE...]", spec)
+		}
+		c, err := criteriaFor(parts[0])
+		if err != nil {
+			return nil, err
+		}
+
+		conditionType := parts[1]
+		if alias, ok := conditionAliases[strings.ToLower(conditionType)]; ok {
+			conditionType = alias
+		}
+		condition := &sheets.BooleanCondition{Type: strings.ToUpper(conditionType)}
+		for _, value := range parts[2:] {
+			condition.Values = append(condition.Values, &sheets.ConditionValue{UserEnteredValue: value})
+		}
+		c.Condition = condition
+	}
+
+	columns := make([]int64, 0, len(criteria))
+	for col := range criteria {
+		columns = append(columns, col)
+	}
+	sort.Slice(columns, func(i, j int) bool { return columns[i] < columns[j] })
+
+	var filterSpecs []*sheets.FilterSpec
+	for _, col := range columns {
+		filterSpecs = append(filterSpecs, &sheets.FilterSpec{
+			ColumnIndex:     col,
+			FilterCriteria:  criteria[col],
+			ForceSendFields: []string{"ColumnIndex"},
+		})
+	}
+
+	return filterSpecs, nil
+}
+
+var clearFilterCmd = &cobra.Command{
+	Use:   "clear-filter <spreadsheet-id> <sheet-name>",
+	Short: "Remove the basic filter of a sheet",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runClearFilter,
+}
+
+func runClearFilter(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.Request{
+		ClearBasicFilter: &sheets.ClearBasicFilterRequest{
+			SheetId:         sheetID,
+			ForceSendFields: []string{"SheetId"},
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to clear basic filter: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status": "success",
+		"sheet":  sheetName,
+	})
+}
+
+var (
+	addFilterViewTitle      string
+	addFilterViewHideValues []string
+	addFilterViewConditions []string
+)
+
+var addFilterViewCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-filter-view <spreadsheet-id> <sheet-name> <range>",
+		Short: "Create a saved filter view on a range",
+		Long: `Create a saved filter view on a range.
+
+Criteria flags work like set-filter (--hide-values "B=Closed", --condition "C:>:100").`,
+		Args: cobra.ExactArgs(3),
+		RunE: runAddFilterView,
+	}
+	cmd.Flags().StringVar(&addFilterViewTitle, "title", "", "Filter view title")
+	cmd.Flags().StringArrayVar(&addFilterViewHideValues, "hide-values", nil, "COLUMN=value1,value2 of values to hide (repeatable)")
+	cmd.Flags().StringArrayVar(&addFilterViewConditions, "condition", nil, "COLUMN:CONDITION[:VALUE...] rows must match (repeatable)")
+	return cmd
+}()
+
+func runAddFilterView(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	rangeA1 := args[2]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	gridRange, err := helpers.ParseGridRange(sheetID, rangeA1)
+	if err != nil {
+		return err
+	}
+
+	filterSpecs, err := buildFilterSpecs(addFilterViewHideValues, addFilterViewConditions)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.Request{
+		AddFilterView: &sheets.AddFilterViewRequest{
+			Filter: &sheets.FilterView{
+				Title:       addFilterViewTitle,
+				Range:       gridRange,
+				FilterSpecs: filterSpecs,
+			},
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	resp, err := service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to add filter view: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"status": "success",
+		"range":  rangeA1,
+		"title":  addFilterViewTitle,
+	}
+	if len(resp.Replies) > 0 && resp.Replies[0].AddFilterView != nil {
+		result["filter_view_id"] = resp.Replies[0].AddFilterView.Filter.FilterViewId
+	}
+
+	return helpers.PrintJSON(result)
+}
+
+var listFilterViewsCmd = &cobra.Command{
+	Use:   "list-filter-views <spreadsheet-id> [sheet-name]",
+	Short: "List filter views",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  runListFilterViews,
+}
+
+func runListFilterViews(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetID).
+		Fields("sheets(properties(sheetId,title,index),filterViews)").Do()
+	if err != nil {
+		return fmt.Errorf("unable to get spreadsheet: %w", err)
+	}
+
+	var onlySheetID *int64
+	if len(args) > 1 {
+		props, err := helpers.FindSheet(spreadsheet, args[1])
+		if err != nil {
+			return err
+		}
+		onlySheetID = &props.SheetId
+	}
+
+	filterViews := []map[string]interface{}{}
+	for _, sheet := range spreadsheet.Sheets {
+		if onlySheetID != nil && sheet.Properties.SheetId != *onlySheetID {
+			continue
+		}
+		for _, view := range sheet.FilterViews {
+			filterViews = append(filterViews, map[string]interface{}{
+				"filter_view_id": view.FilterViewId,
+				"title":          view.Title,
+				"sheet_name":     sheet.Properties.Title,
+				"range":          helpers.GridRangeToA1(view.Range),
+				"criteria":       len(view.FilterSpecs),
+			})
+		}
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":       "success",
+		"filter_views": filterViews,
+	})
+}
+
+var (
+	updateFilterViewTitle      string
+	updateFilterViewRange      string
+	updateFilterViewHideValues []string
+	updateFilterViewConditions []string
+)
+
+var updateFilterViewCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-filter-view <spreadsheet-id> <filter-view-id>",
+		Short: "Update the title, range or criteria of a filter view",
+		Long: `Update the title, range or criteria of a filter view.
+
+Only the given settings change. Criteria flags replace all existing criteria.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runUpdateFilterView,
+	}
+	cmd.Flags().StringVar(&updateFilterViewTitle, "title", "", "New title")
+	cmd.Flags().StringVar(&updateFilterViewRange, "range", "", "New sheet-qualified range (e.g. Sheet1!A1:F200)")
+	cmd.Flags().StringArrayVar(&updateFilterViewHideValues, "hide-values", nil, "COLUMN=value1,value2 of values to hide (repeatable)")
+	cmd.Flags().StringArrayVar(&updateFilterViewConditions, "condition", nil, "COLUMN:CONDITION[:VALUE...] rows must match (repeatable)")
+	return cmd
+}()
+
+func runUpdateFilterView(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	filterViewID, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid filter view ID: %s", args[1])
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	filterView := &sheets.FilterView{FilterViewId: filterViewID}
+	var fields []string
+
+	if cmd.Flags().Changed("title") {
+		filterView.Title = updateFilterViewTitle
+		fields = append(fields, "title")
+	}
+	if updateFilterViewRange != "" {
+		spreadsheet, err := helpers.GetSpreadsheetSheets(service, spreadsheetID)
+		if err != nil {
+			return fmt.Errorf("unable to get spreadsheet: %w", err)
+		}
+		filterView.Range, err = resolveSheetGridRange(spreadsheet, updateFilterViewRange)
+		if err != nil {
+			return err
+		}
+		fields = append(fields, "range")
+	}
+	if len(updateFilterViewHideValues) > 0 || len(updateFilterViewConditions) > 0 {
+		filterView.FilterSpecs, err = buildFilterSpecs(updateFilterViewHideValues, updateFilterViewConditions)
+		if err != nil {
+			return err
+		}
+		fields = append(fields, "filterSpecs")
+	}
+
+	if len(fields) == 0 {
+		return fmt.Errorf("nothing to update: use --title, --range, --hide-values or --condition")
+	}
+
+	req := &sheets.Request{
+		UpdateFilterView: &sheets.UpdateFilterViewRequest{
+			Filter: filterView,
+			Fields: strings.Join(fields, ","),
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to update filter view: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":         "success",
+		"filter_view_id": filterViewID,
+		"updated":        fields,
+	})
+}
+
+var deleteFilterViewCmd = &cobra.Command{
+	Use:   "delete-filter-view <spreadsheet-id> <filter-view-id>",
+	Short: "Delete a filter view",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runDeleteFilterView,
+}
+
+func runDeleteFilterView(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	filterViewID, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid filter view ID: %s", args[1])
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.Request{
+		DeleteFilterView: &sheets.DeleteFilterViewRequest{
+			FilterId: filterViewID,
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to delete filter view: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":         "success",
+		"filter_view_id": filterViewID,
+	})
+}
@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+var (
+	findReplaceSheet           string
+	findReplaceRange           string
+	findReplaceRegex           bool
+	findReplaceMatchCase       bool
+	findReplaceMatchEntireCell bool
+	findReplaceIncludeFormulas bool
+)
+
+var findReplaceCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "find-replace <spreadsheet-id> <find> <replace>",
+		Short: "Find and replace text across all sheets, a sheet, or a range",
+		Long: `Find and replace text across all sheets, a sheet (--sheet), or a range (--sheet with --range).
+
+With --regex, <find> is a regular expression and <replace> may reference capture groups ($1, $2...).`,
+		Args: cobra.ExactArgs(3),
+		RunE: runFindReplace,
+	}
+	cmd.Flags().StringVar(&findReplaceSheet, "sheet", "", "Limit to this sheet (default: all sheets)")
+	cmd.Flags().StringVar(&findReplaceRange, "range", "", "Limit to this range of --sheet (e.g. A1:D20)")
+	cmd.Flags().BoolVar(&findReplaceRegex, "regex", false, "Treat <find> as a regular expression")
+	cmd.Flags().BoolVar(&findReplaceMatchCase, "match-case", false, "Case-sensitive search")
+	cmd.Flags().BoolVar(&findReplaceMatchEntireCell, "match-entire-cell", false, "Only match cells whose whole content matches")
+	cmd.Flags().BoolVar(&findReplaceIncludeFormulas, "include-formulas", false, "Also search inside formulas")
+	return cmd
+}()
+
+func runFindReplace(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	if findReplaceRange != "" && findReplaceSheet == "" {
+		return fmt.Errorf("--range requires --sheet")
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	findReplace := &sheets.FindReplaceRequest{
+		Find:            args[1],
+		Replacement:     args[2],
+		SearchByRegex:   findReplaceRegex,
+		MatchCase:       findReplaceMatchCase,
+		MatchEntireCell: findReplaceMatchEntireCell,
+		IncludeFormulas: findReplaceIncludeFormulas,
+		// Replacing with an empty string must still be sent
+		ForceSendFields: []string{"Replacement"},
+	}
+
+	switch {
+	case findReplaceRange != "":
+		sheetID, err := helpers.GetSheetID(service, spreadsheetID, findReplaceSheet)
+		if err != nil {
+			return err
+		}
+		findReplace.Range, err = helpers.ParseGridRange(sheetID, findReplaceRange)
+		if err != nil {
+			return err
+		}
+	case findReplaceSheet != "":
+		sheetID, err := helpers.GetSheetID(service, spreadsheetID, findReplaceSheet)
+		if err != nil {
+			return err
+		}
+		findReplace.SheetId = sheetID
+		findReplace.ForceSendFields = append(findReplace.ForceSendFields, "SheetId")
+	default:
+		findReplace.AllSheets = true
+	}
+
+	req := &sheets.Request{
+		FindReplace: findReplace,
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	resp, err := service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to find and replace: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"status":       "success",
+		"replacements": int64(0),
+	}
+	if len(resp.Replies) > 0 && resp.Replies[0].FindReplace != nil {
+		reply := resp.Replies[0].FindReplace
+		result["replacements"] = reply.OccurrencesChanged
+		result["values_changed"] = reply.ValuesChanged
+		result["formulas_changed"] = reply.FormulasChanged
+		result["rows_changed"] = reply.RowsChanged
+		result["sheets_changed"] = reply.SheetsChanged
+	}
+
+	return helpers.PrintJSON(result)
+}
+
+var (
+	searchSheet           string
+	searchRange           string
+	searchRegex           bool
+	searchMatchCase       bool
+	searchMatchEntireCell bool
+)
+
+var searchCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search <spreadsheet-id> <query>",
+		Short: "Find cells containing a value across all sheets, a sheet, or a range",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runSearch,
+	}
+	cmd.Flags().StringVar(&searchSheet, "sheet", "", "Limit to this sheet (default: all sheets)")
+	cmd.Flags().StringVar(&searchRange, "range", "", "Limit to this range of --sheet (e.g. A1:D20)")
+	cmd.Flags().BoolVar(&searchRegex, "regex", false, "Treat <query> as a regular expression")
+	cmd.Flags().BoolVar(&searchMatchCase, "match-case", false, "Case-sensitive search")
+	cmd.Flags().BoolVar(&searchMatchEntireCell, "match-entire-cell", false, "Only match cells whose whole content matches")
+	return cmd
+}()
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	if searchRange != "" && searchSheet == "" {
+		return fmt.Errorf("--range requires --sheet")
+	}
+
+	pattern := args[1]
+	if !searchRegex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if searchMatchEntireCell {
+		pattern = "^(?:" + pattern + ")$"
+	}
+	if !searchMatchCase {
+		pattern = "(?i)" + pattern
+	}
+	matcher, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("invalid regular expression: %w", err)
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	var titles []string
+	if searchSheet != "" {
+		title, err := helpers.ResolveSheetTitle(service, spreadsheetID, searchSheet)
+		if err != nil {
+			return err
+		}
+		titles = append(titles, title)
+	} else {
+		spreadsheet, err := helpers.GetSpreadsheetSheets(service, spreadsheetID)
+		if err != nil {
+			return fmt.Errorf("unable to get spreadsheet: %w", err)
+		}
+		for _, sheet := range spreadsheet.Sheets {
+			titles = append(titles, sheet.Properties.Title)
+		}
+	}
+
+	ranges := make([]string, len(titles))
+	for i, title := range titles {
+		ranges[i] = helpers.SheetRange(title, searchRange)
+	}
+
+	resp, err := service.Spreadsheets.Values.BatchGet(spreadsheetID).Ranges(ranges...).Do()
+	if err != nil {
+		return fmt.Errorf("unable to read values: %w", err)
+	}
+
+	matches := []map[string]interface{}{}
+	for i, valueRange := range resp.ValueRanges {
+		startCol, startRow, _, _, err := helpers.ParseRange(helpers.StripSheetName(valueRange.Range))
+		if err != nil {
+			return err
+		}
+		// Whole-row or whole-column ranges leave one coordinate unset
+		startCol = max(startCol, 0)
+		startRow = max(startRow, 0)
+
+		for r, row := range valueRange.Values {
+			for c, cell := range row {
+				value := helpers.CellString(cell)
+				if value == "" || !matcher.MatchString(value) {
+					continue
+				}
+				matches = append(matches, map[string]interface{}{
+					"sheet": titles[i],
+					"cell":  helpers.GridToA1(startCol+c, startRow+r),
+					"value": value,
+				})
+			}
+		}
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":  "success",
+		"query":   args[1],
+		"count":   len(matches),
+		"matches": matches,
+	})
+}
@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"google.golang.org/api/sheets/v4"
@@ -41,36 +42,111 @@ func runFormatCells(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	startCol, startRow, endCol, endRow, err := helpers.ParseRange(rangeA1)
+	gridRange, err := boundedGridRange(sheetID, rangeA1)
 	if err != nil {
 		return err
 	}
 
-	pattern := formatCellsPattern
+	req := numberFormatRequest(gridRange, formatType, formatCellsPattern)
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to format cells: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]string{
+		"status": "success",
+		"format": formatType,
+	})
+}
+
+// numberFormatRequest applies a number format to a range, with the default pattern of the type
+// when pattern is empty
+func numberFormatRequest(gridRange *sheets.GridRange, formatType, pattern string) *sheets.Request {
 	if pattern == "" {
 		pattern = helpers.GetDefaultFormatPattern(formatType)
 	}
 
-	numFormat := &sheets.NumberFormat{
-		Type:    formatType,
-		Pattern: pattern,
+	format := &sheets.CellFormat{
+		NumberFormat: &sheets.NumberFormat{
+			Type:    formatType,
+			Pattern: pattern,
+		},
 	}
+	return cellFormatRequest(gridRange, format, []string{"userEnteredFormat.numberFormat"})
+}
 
-	req := &sheets.Request{
+// cellFormatRequest applies the given fields of a format to every cell of a range
+func cellFormatRequest(gridRange *sheets.GridRange, format *sheets.CellFormat, fields []string) *sheets.Request {
+	return &sheets.Request{
 		RepeatCell: &sheets.RepeatCellRequest{
-			Range: &sheets.GridRange{
-				SheetId:          sheetID,
-				StartRowIndex:    int64(startRow),
-				EndRowIndex:      int64(endRow + 1),
-				StartColumnIndex: int64(startCol),
-				EndColumnIndex:   int64(endCol + 1),
-			},
+			Range: gridRange,
 			Cell: &sheets.CellData{
-				UserEnteredFormat: &sheets.CellFormat{
-					NumberFormat: numFormat,
-				},
+				UserEnteredFormat: format,
 			},
-			Fields: "userEnteredFormat.numberFormat",
+			Fields: strings.Join(fields, ","),
+		},
+	}
+}
+
+// boundedGridRange converts a cell range such as "A1:D10" into a grid range
+func boundedGridRange(sheetID int64, rangeA1 string) (*sheets.GridRange, error) {
+	startCol, startRow, endCol, endRow, err := helpers.ParseRange(rangeA1)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sheets.GridRange{
+		SheetId:          sheetID,
+		StartRowIndex:    int64(startRow),
+		EndRowIndex:      int64(endRow + 1),
+		StartColumnIndex: int64(startCol),
+		EndColumnIndex:   int64(endCol + 1),
+	}, nil
+}
+
+var mergeCellsType string
+
+var mergeCellsCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "merge-cells <spreadsheet-id> <sheet-name> <range>",
+		Short: "Merge cells (MERGE_ALL, MERGE_COLUMNS, MERGE_ROWS)",
+		Args:  cobra.ExactArgs(3),
+		RunE:  runMergeCells,
+	}
+	cmd.Flags().StringVar(&mergeCellsType, "type", MergeTypeAll, "Merge type (MERGE_ALL, MERGE_COLUMNS, MERGE_ROWS)")
+	return cmd
+}()
+
+func runMergeCells(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	rangeA1 := args[2]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	gridRange, err := helpers.ParseGridRange(sheetID, rangeA1)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.Request{
+		MergeCells: &sheets.MergeCellsRequest{
+			Range:     gridRange,
+			MergeType: mergeCellsType,
 		},
 	}
 
@@ -80,11 +156,174 @@ func runFormatCells(cmd *cobra.Command, args []string) error {
 
 	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
 	if err != nil {
-		return fmt.Errorf("unable to format cells: %w", err)
+		return fmt.Errorf("unable to merge cells: %w", err)
 	}
 
 	return helpers.PrintJSON(map[string]string{
 		"status": "success",
-		"format": formatType,
+		"range":  rangeA1,
+		"type":   mergeCellsType,
 	})
 }
+
+var unmergeCellsCmd = &cobra.Command{
+	Use:   "unmerge-cells <spreadsheet-id> <sheet-name> <range>",
+	Short: "Unmerge all merged cells in a range",
+	Args:  cobra.ExactArgs(3),
+	RunE:  runUnmergeCells,
+}
+
+func runUnmergeCells(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	rangeA1 := args[2]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	gridRange, err := helpers.ParseGridRange(sheetID, rangeA1)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.Request{
+		UnmergeCells: &sheets.UnmergeCellsRequest{
+			Range: gridRange,
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to unmerge cells: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]string{
+		"status": "success",
+		"range":  rangeA1,
+	})
+}
+
+var getFormatCmd = &cobra.Command{
+	Use:   "get-format <spreadsheet-id> <sheet-name> <range>",
+	Short: "Show user-entered and effective formatting of cells",
+	Args:  cobra.ExactArgs(3),
+	RunE:  runGetFormat,
+}
+
+func runGetFormat(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	rangeA1 := args[2]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetTitle, err := helpers.ResolveSheetTitle(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetID).
+		Ranges(helpers.SheetRange(sheetTitle, rangeA1)).
+		IncludeGridData(true).
+		Fields("sheets(data(startRow,startColumn,rowData(values(userEnteredFormat,effectiveFormat))))").
+		Do()
+	if err != nil {
+		return fmt.Errorf("unable to get cell formats: %w", err)
+	}
+
+	cells := map[string]interface{}{}
+	for _, sheet := range spreadsheet.Sheets {
+		for _, data := range sheet.Data {
+			for i, row := range data.RowData {
+				for j, cell := range row.Values {
+					if cell.UserEnteredFormat == nil && cell.EffectiveFormat == nil {
+						continue
+					}
+					address := helpers.GridToA1(int(data.StartColumn)+j, int(data.StartRow)+i)
+					cells[address] = map[string]interface{}{
+						"user_entered": describeCellFormat(cell.UserEnteredFormat),
+						"effective":    describeCellFormat(cell.EffectiveFormat),
+					}
+				}
+			}
+		}
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status": "success",
+		"range":  rangeA1,
+		"cells":  cells,
+	})
+}
+
+// describeCellFormat flattens a CellFormat into a readable map with hex colors
+func describeCellFormat(format *sheets.CellFormat) map[string]interface{} {
+	if format == nil {
+		return nil
+	}
+
+	desc := map[string]interface{}{}
+	if format.NumberFormat != nil {
+		desc["number_format"] = map[string]string{
+			"type":    format.NumberFormat.Type,
+			"pattern": format.NumberFormat.Pattern,
+		}
+	}
+	if format.BackgroundColor != nil {
+		desc["background_color"] = helpers.ColorToHex(format.BackgroundColor)
+	}
+	if format.HorizontalAlignment != "" {
+		desc["horizontal_alignment"] = format.HorizontalAlignment
+	}
+	if format.VerticalAlignment != "" {
+		desc["vertical_alignment"] = format.VerticalAlignment
+	}
+	if format.WrapStrategy != "" {
+		desc["wrap_strategy"] = format.WrapStrategy
+	}
+	if format.TextRotation != nil {
+		desc["text_rotation"] = map[string]interface{}{
+			"angle":    format.TextRotation.Angle,
+			"vertical": format.TextRotation.Vertical,
+		}
+	}
+	if tf := format.TextFormat; tf != nil {
+		text := map[string]interface{}{
+			"bold":          tf.Bold,
+			"italic":        tf.Italic,
+			"underline":     tf.Underline,
+			"strikethrough": tf.Strikethrough,
+		}
+		if tf.FontFamily != "" {
+			text["font_family"] = tf.FontFamily
+		}
+		if tf.FontSize > 0 {
+			text["font_size"] = tf.FontSize
+		}
+		if tf.ForegroundColor != nil {
+			text["color"] = helpers.ColorToHex(tf.ForegroundColor)
+		}
+		desc["text_format"] = text
+	}
+	if format.Borders != nil {
+		desc["borders"] = format.Borders
+	}
+
+	return desc
+}
@@ -0,0 +1,238 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+var (
+	exportHTMLWithStyle bool
+	exportHTMLOutput    string
+)
+
+var exportHTMLCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-html <spreadsheet-id> <sheet-name> [range]",
+		Short: "Export a sheet or range as an HTML table",
+		Long: `Export a sheet or range as an HTML table with the first row as header.
+
+With --with-style, background colors, fonts, alignment and borders are taken
+from each cell's effective format and inlined as style attributes, so the
+table renders the same in email clients that ignore stylesheets.`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: runExportHTML,
+	}
+	cmd.Flags().BoolVar(&exportHTMLWithStyle, "with-style", false, "Inline cell formatting as CSS")
+	cmd.Flags().StringVarP(&exportHTMLOutput, "output", "o", "", "Write to a file instead of stdout")
+	return cmd
+}()
+
+func runExportHTML(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	rangeA1 := ""
+	if len(args) == 3 {
+		rangeA1 = args[2]
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetTitle, err := helpers.ResolveSheetTitle(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	fields := googleapi.Field("sheets(data(rowData(values(formattedValue))))")
+	if exportHTMLWithStyle {
+		fields = "sheets(data(rowData(values(formattedValue,effectiveFormat(backgroundColor,horizontalAlignment,textFormat,borders)))))"
+	}
+
+	fullRange := helpers.SheetRange(sheetTitle, rangeA1)
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetID).
+		Ranges(fullRange).
+		IncludeGridData(true).
+		Fields(fields).
+		Do()
+	if err != nil {
+		return fmt.Errorf("unable to get sheet data: %w", err)
+	}
+
+	var rows []*sheets.RowData
+	for _, sheet := range spreadsheet.Sheets {
+		for _, data := range sheet.Data {
+			rows = append(rows, data.RowData...)
+		}
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("no data in %s", fullRange)
+	}
+
+	table := htmlTable(rows, exportHTMLWithStyle)
+
+	if exportHTMLOutput == "" {
+		fmt.Print(table)
+		return nil
+	}
+
+	if err := os.WriteFile(exportHTMLOutput, []byte(table), 0644); err != nil {
+		return fmt.Errorf("unable to write HTML file: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status": "success",
+		"file":   exportHTMLOutput,
+		"rows":   len(rows) - 1,
+	})
+}
+
+// htmlTable renders grid rows as a table whose first row is the header
+func htmlTable(rows []*sheets.RowData, withStyle bool) string {
+	width := 0
+	for _, row := range rows {
+		width = max(width, len(row.Values))
+	}
+
+	var b strings.Builder
+	writeRow := func(row *sheets.RowData, tag string) {
+		b.WriteString("    <tr>")
+		for i := 0; i < width; i++ {
+			var cell *sheets.CellData
+			if i < len(row.Values) {
+				cell = row.Values[i]
+			}
+
+			b.WriteString("<" + tag)
+			if withStyle && cell != nil {
+				if style := cellCSS(cell.EffectiveFormat); style != "" {
+					b.WriteString(` style="` + html.EscapeString(style) + `"`)
+				}
+			}
+			b.WriteString(">")
+			if cell != nil {
+				b.WriteString(strings.ReplaceAll(html.EscapeString(cell.FormattedValue), "\n", "<br>"))
+			}
+			b.WriteString("</" + tag + ">")
+		}
+		b.WriteString("</tr>\n")
+	}
+
+	b.WriteString("<table")
+	if withStyle {
+		b.WriteString(` style="border-collapse: collapse"`)
+	}
+	b.WriteString(">\n  <thead>\n")
+	writeRow(rows[0], "th")
+	b.WriteString("  </thead>\n  <tbody>\n")
+	for _, row := range rows[1:] {
+		writeRow(row, "td")
+	}
+	b.WriteString("  </tbody>\n</table>\n")
+
+	return b.String()
+}
+
+// cellCSS converts an effective cell format to inline CSS, leaving out Sheets defaults
+func cellCSS(format *sheets.CellFormat) string {
+	if format == nil {
+		return ""
+	}
+
+	var css []string
+	if bg := helpers.ColorToHex(format.BackgroundColor); bg != "" && bg != "#ffffff" {
+		css = append(css, "background-color: "+bg)
+	}
+	if align := strings.ToLower(format.HorizontalAlignment); align != "" {
+		css = append(css, "text-align: "+align)
+	}
+
+	if tf := format.TextFormat; tf != nil {
+		if color := helpers.ColorToHex(tf.ForegroundColor); color != "" && color != "#000000" {
+			css = append(css, "color: "+color)
+		}
+		if tf.FontFamily != "" {
+			css = append(css, "font-family: "+tf.FontFamily)
+		}
+		if tf.FontSize > 0 {
+			css = append(css, fmt.Sprintf("font-size: %dpt", tf.FontSize))
+		}
+		if tf.Bold {
+			css = append(css, "font-weight: bold")
+		}
+		if tf.Italic {
+			css = append(css, "font-style: italic")
+		}
+		var decorations []string
+		if tf.Underline {
+			decorations = append(decorations, "underline")
+		}
+		if tf.Strikethrough {
+			decorations = append(decorations, "line-through")
+		}
+		if len(decorations) > 0 {
+			css = append(css, "text-decoration: "+strings.Join(decorations, " "))
+		}
+	}
+
+	if borders := format.Borders; borders != nil {
+		for _, side := range []struct {
+			name   string
+			border *sheets.Border
+		}{
+			{"top", borders.Top},
+			{"right", borders.Right},
+			{"bottom", borders.Bottom},
+			{"left", borders.Left},
+		} {
+			if value := borderCSS(side.border); value != "" {
+				css = append(css, "border-"+side.name+": "+value)
+			}
+		}
+	}
+
+	return strings.Join(css, "; ")
+}
+
+// borderCSS maps a Sheets border style to a CSS border shorthand
+func borderCSS(border *sheets.Border) string {
+	if border == nil {
+		return ""
+	}
+
+	var value string
+	switch border.Style {
+	case "SOLID":
+		value = "1px solid"
+	case "SOLID_MEDIUM":
+		value = "2px solid"
+	case "SOLID_THICK":
+		value = "3px solid"
+	case "DASHED":
+		value = "1px dashed"
+	case "DOTTED":
+		value = "1px dotted"
+	case "DOUBLE":
+		value = "3px double"
+	default:
+		return ""
+	}
+
+	color := helpers.ColorToHex(border.Color)
+	if color == "" {
+		color = "#000000"
+	}
+	return value + " " + color
+}
@@ -0,0 +1,225 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/drive/v3"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+// imageModes maps insert-image --mode values to the mode argument of the IMAGE function
+var imageModes = map[string]int{
+	"fit":      1,
+	"stretch":  2,
+	"original": 3,
+}
+
+var (
+	insertImageMode   string
+	insertImageWidth  int64
+	insertImageHeight int64
+	insertImageFolder string
+	insertImagePublic bool
+)
+
+var insertImageCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "insert-image <spreadsheet-id> <sheet-name> <cell> <image-url|file>",
+		Short: "Put an image into a cell from a URL or a local file",
+		Long: `Put an image into a cell with the IMAGE function, from a URL or a local file.
+
+IMAGE can only load public URLs, so a local file must be uploaded to Drive and
+shared with anyone who has the link: pass --public-upload to allow it. The
+result reports "shared_publicly" and the file ID; "delete <file-id>" trashes
+the upload to take it offline. --mode chooses how the image fills
+the cell; --width and --height set an exact size in pixels and resize the
+row and column to match.
+
+Floating images placed over the grid cannot be created through the Sheets
+API, so only in-cell images are supported.`,
+		Args: cobra.ExactArgs(4),
+		RunE: runInsertImage,
+	}
+	cmd.Flags().StringVar(&insertImageMode, "mode", "fit", "Sizing: fit (keep aspect ratio), stretch or original")
+	cmd.Flags().Int64Var(&insertImageWidth, "width", 0, "Image width in pixels (with --height)")
+	cmd.Flags().Int64Var(&insertImageHeight, "height", 0, "Image height in pixels (with --width)")
+	cmd.Flags().StringVar(&insertImageFolder, "folder", "", "Drive folder for uploaded image files")
+	cmd.Flags().BoolVar(&insertImagePublic, "public-upload", false, "Allow uploading a local file to Drive and sharing it with anyone who has the link")
+	cmd.MarkFlagsRequiredTogether("width", "height")
+	return cmd
+}()
+
+func runInsertImage(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	cell := args[2]
+	source := args[3]
+
+	customSize := insertImageWidth > 0 || insertImageHeight > 0
+	if customSize && (insertImageWidth <= 0 || insertImageHeight <= 0) {
+		return fmt.Errorf("--width and --height must be positive")
+	}
+	mode, ok := imageModes[insertImageMode]
+	if !ok {
+		return fmt.Errorf("invalid --mode '%s': expected fit, stretch or original", insertImageMode)
+	}
+
+	col, row, err := helpers.A1ToGrid(cell)
+	if err != nil || col < 0 || row < 0 {
+		return fmt.Errorf("invalid cell reference: %s", cell)
+	}
+
+	isURL := strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://")
+	if !isURL && !insertImagePublic {
+		return fmt.Errorf("%s is a local file: it must be uploaded to Drive and shared with anyone who has the link for IMAGE to load it; pass --public-upload to allow this", source)
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	imageURL := source
+	fileID := ""
+	if !isURL {
+		fileID, err = uploadPublicImage(ctx, source, insertImageFolder)
+		if err != nil {
+			return err
+		}
+		imageURL = fmt.Sprintf(DriveImageURLPattern, fileID)
+	}
+
+	quotedURL := `"` + strings.ReplaceAll(imageURL, `"`, `""`) + `"`
+	formula := fmt.Sprintf("=IMAGE(%s, %d)", quotedURL, mode)
+	if customSize {
+		formula = fmt.Sprintf("=IMAGE(%s, 4, %d, %d)", quotedURL, insertImageHeight, insertImageWidth)
+	}
+
+	requests := []*sheets.Request{
+		{
+			UpdateCells: &sheets.UpdateCellsRequest{
+				Range: &sheets.GridRange{
+					SheetId:          sheetID,
+					StartRowIndex:    int64(row),
+					EndRowIndex:      int64(row + 1),
+					StartColumnIndex: int64(col),
+					EndColumnIndex:   int64(col + 1),
+				},
+				Rows: []*sheets.RowData{
+					{
+						Values: []*sheets.CellData{
+							{UserEnteredValue: &sheets.ExtendedValue{FormulaValue: &formula}},
+						},
+					},
+				},
+				Fields: "userEnteredValue",
+			},
+		},
+	}
+	if customSize {
+		requests = append(requests,
+			imageDimensionRequest(sheetID, MajorDimensionRows, int64(row), insertImageHeight),
+			imageDimensionRequest(sheetID, MajorDimensionColumns, int64(col), insertImageWidth),
+		)
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: requests,
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		err = fmt.Errorf("unable to insert image: %w", err)
+		if fileID != "" {
+			driveService, driveErr := auth.GetDriveService(ctx)
+			if driveErr != nil {
+				return fmt.Errorf("%w (uploaded image %s is still shared by link: %v)", err, fileID, driveErr)
+			}
+			return discardUploadedImage(driveService, fileID, err)
+		}
+		return err
+	}
+
+	result := map[string]interface{}{
+		"status":  "success",
+		"cell":    cell,
+		"formula": formula,
+	}
+	if fileID != "" {
+		result["file_id"] = fileID
+		result["shared_publicly"] = true
+	}
+	return helpers.PrintJSON(result)
+}
+
+// imageDimensionRequest sets the pixel size of one row or column
+func imageDimensionRequest(sheetID int64, dimension string, index, pixels int64) *sheets.Request {
+	return &sheets.Request{
+		UpdateDimensionProperties: &sheets.UpdateDimensionPropertiesRequest{
+			Range: &sheets.DimensionRange{
+				SheetId:    sheetID,
+				Dimension:  dimension,
+				StartIndex: index,
+				EndIndex:   index + 1,
+			},
+			Properties: &sheets.DimensionProperties{PixelSize: pixels},
+			Fields:     "pixelSize",
+		},
+	}
+}
+
+// uploadPublicImage uploads an image file to Drive and lets anyone with the link read it
+func uploadPublicImage(ctx context.Context, path, folderID string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to open image: %w", err)
+	}
+	defer file.Close()
+
+	driveService, err := auth.GetDriveService(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	metadata := &drive.File{Name: filepath.Base(path)}
+	if folderID != "" {
+		metadata.Parents = []string{folderID}
+	}
+
+	uploaded, err := driveService.Files.Create(metadata).Media(file).Fields("id,mimeType").Do()
+	if err != nil {
+		return "", fmt.Errorf("unable to upload image: %w", err)
+	}
+	if !strings.HasPrefix(uploaded.MimeType, "image/") {
+		_ = driveService.Files.Delete(uploaded.Id).Do()
+		return "", fmt.Errorf("%s is not an image (detected %s)", path, uploaded.MimeType)
+	}
+
+	if err := setLinkPermission(driveService, uploaded.Id, "anyone", "", "reader"); err != nil {
+		return "", discardUploadedImage(driveService, uploaded.Id, err)
+	}
+	return uploaded.Id, nil
+}
+
+// discardUploadedImage deletes an uploaded image that ended up unused and returns cause,
+// naming the file when it could not be deleted so it can be removed by hand
+func discardUploadedImage(driveService *drive.Service, fileID string, cause error) error {
+	if err := driveService.Files.Delete(fileID).Do(); err != nil {
+		return fmt.Errorf("%w (uploaded image %s could not be deleted: %v)", cause, fileID, err)
+	}
+	return cause
+}
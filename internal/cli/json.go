@@ -0,0 +1,381 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+var (
+	importJSONColumns   []string
+	importJSONStartCell string
+)
+
+var importJSONCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-json <spreadsheet-id> <sheet-name> <file.json>",
+		Short: "Import an array of JSON objects as a header row plus data rows",
+		Long: `Import an array of JSON objects as a header row plus data rows.
+
+The header is made of the object keys in first-seen order; --columns selects
+and orders them explicitly. Nested objects and arrays are written as JSON text.
+Strings are written as-is (never parsed as numbers, dates or formulas).`,
+		Args: cobra.ExactArgs(3),
+		RunE: runImportJSON,
+	}
+	cmd.Flags().StringSliceVar(&importJSONColumns, "columns", nil, "Comma-separated keys to write, in order (default: all keys)")
+	cmd.Flags().StringVar(&importJSONStartCell, "start", DefaultStartCell, "Starting cell")
+	return cmd
+}()
+
+func runImportJSON(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	jsonPath := args[2]
+
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		return fmt.Errorf("unable to read JSON file: %w", err)
+	}
+
+	records, keys, err := decodeRecords(data)
+	if err != nil {
+		return err
+	}
+
+	columns := keys
+	if len(importJSONColumns) > 0 {
+		columns = importJSONColumns
+	}
+
+	values := recordsToValues(records, columns)
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetTitle, err := helpers.ResolveSheetTitle(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	valueRange := &sheets.ValueRange{
+		Values: values,
+	}
+
+	_, err = service.Spreadsheets.Values.Update(
+		spreadsheetID,
+		helpers.SheetRange(sheetTitle, importJSONStartCell),
+		valueRange,
+	).ValueInputOption(ValueInputModeRaw).Do()
+
+	if err != nil {
+		return fmt.Errorf("unable to import JSON: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":  "success",
+		"rows":    len(records),
+		"columns": columns,
+	})
+}
+
+// decodeRecords parses a JSON array of objects, returning the records and their keys in first-seen order
+func decodeRecords(data []byte) ([]map[string]interface{}, []string, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON: expected an array of objects: %w", err)
+	}
+
+	var keys []string
+	seen := map[string]bool{}
+	records := make([]map[string]interface{}, 0, len(raw))
+
+	for i, item := range raw {
+		record := map[string]interface{}{}
+		if err := json.Unmarshal(item, &record); err != nil {
+			return nil, nil, fmt.Errorf("invalid JSON record %d: expected an object", i)
+		}
+		records = append(records, record)
+
+		// Walk the object tokens again to recover the key order lost by the map
+		dec := json.NewDecoder(bytes.NewReader(item))
+		if _, err := dec.Token(); err != nil {
+			return nil, nil, err
+		}
+		for dec.More() {
+			token, err := dec.Token()
+			if err != nil {
+				return nil, nil, err
+			}
+			key := token.(string)
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	return records, keys, nil
+}
+
+// recordsToValues builds a header row plus one row per record, in column order
+func recordsToValues(records []map[string]interface{}, columns []string) [][]interface{} {
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = column
+	}
+
+	values := [][]interface{}{header}
+	for _, record := range records {
+		row := make([]interface{}, len(columns))
+		for i, column := range columns {
+			switch v := record[column].(type) {
+			case nil:
+				row[i] = ""
+			case map[string]interface{}, []interface{}:
+				encoded, _ := json.Marshal(v)
+				row[i] = string(encoded)
+			default:
+				row[i] = v
+			}
+		}
+		values = append(values, row)
+	}
+
+	return values
+}
+
+var (
+	exportJSONTypes    string
+	exportJSONFormat   string
+	exportJSONOutput   string
+	exportJSONPageSize int
+)
+
+var exportJSONCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-json <spreadsheet-id> <sheet-name> [range]",
+		Short: "Export rows as an array of JSON objects keyed by the header row",
+		Long: `Export rows as an array of JSON objects keyed by the header row.
+
+With --types=string (default) every value is the formatted text shown in the
+sheet. With --types=infer, numbers and booleans keep their JSON types (dates
+stay formatted strings). Empty header cells are named after their column letter.
+
+With --format=ndjson, one compact object per line is written and the sheet is
+read in windows of --page-size rows, each flushed as soon as it arrives.`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: runExportJSON,
+	}
+	cmd.Flags().StringVar(&exportJSONTypes, "types", "string", "Value types: string or infer")
+	cmd.Flags().StringVar(&exportJSONFormat, "format", "json", "Output format: json (array) or ndjson (one object per line)")
+	cmd.Flags().StringVarP(&exportJSONOutput, "output", "o", "", "Write to a file instead of stdout")
+	cmd.Flags().IntVar(&exportJSONPageSize, "page-size", helpers.DefaultPageSize, "Rows fetched per request in ndjson mode")
+	return cmd
+}()
+
+func runExportJSON(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	rangeA1 := ""
+	if len(args) == 3 {
+		rangeA1 = args[2]
+	}
+
+	valueRender := ValueRenderFormatted
+	switch exportJSONTypes {
+	case "string":
+	case "infer":
+		valueRender = ValueRenderUnformatted
+	default:
+		return fmt.Errorf("invalid --types '%s': expected string or infer", exportJSONTypes)
+	}
+	if exportJSONFormat != "json" && exportJSONFormat != "ndjson" {
+		return fmt.Errorf("invalid --format '%s': expected json or ndjson", exportJSONFormat)
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	out := os.Stdout
+	if exportJSONOutput != "" {
+		out, err = os.Create(exportJSONOutput)
+		if err != nil {
+			return fmt.Errorf("unable to create JSON file: %w", err)
+		}
+		defer out.Close()
+	}
+
+	var count int
+	if exportJSONFormat == "ndjson" && rangeA1 == "" {
+		sheet, err := helpers.GetSheetProperties(service, spreadsheetID, sheetName)
+		if err != nil {
+			return err
+		}
+		count, err = streamNDJSON(service, spreadsheetID, sheet, valueRender, out)
+		if err != nil {
+			return err
+		}
+	} else {
+		sheetTitle, err := helpers.ResolveSheetTitle(service, spreadsheetID, sheetName)
+		if err != nil {
+			return err
+		}
+
+		resp, err := service.Spreadsheets.Values.Get(spreadsheetID, helpers.SheetRange(sheetTitle, rangeA1)).
+			ValueRenderOption(valueRender).
+			DateTimeRenderOption(DateRenderFormatted).Do()
+		if err != nil {
+			return fmt.Errorf("unable to get sheet data: %w", err)
+		}
+
+		var headers []string
+		var rows [][]interface{}
+		if len(resp.Values) > 0 {
+			headers = recordHeaders(resp.Values[0])
+			rows = resp.Values[1:]
+		}
+		count = len(rows)
+
+		if exportJSONFormat == "ndjson" {
+			err = writeNDJSONRecords(out, headers, rows)
+		} else {
+			err = writeJSONRecords(out, headers, rows)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if exportJSONOutput == "" {
+		return nil
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status": "success",
+		"file":   exportJSONOutput,
+		"rows":   count,
+	})
+}
+
+// streamNDJSON pages through a sheet and writes one object per data row, flushing after each page
+func streamNDJSON(service *sheets.Service, spreadsheetID string, sheet *sheets.SheetProperties, valueRender string, out io.Writer) (int, error) {
+	var headers []string
+	count := 0
+
+	err := helpers.StreamRows(service, spreadsheetID, sheet, exportJSONPageSize, valueRender, DateRenderFormatted, func(rows [][]interface{}) error {
+		if headers == nil {
+			headers = recordHeaders(rows[0])
+			rows = rows[1:]
+		}
+		count += len(rows)
+		return writeNDJSONRecords(out, headers, rows)
+	})
+
+	return count, err
+}
+
+// writeNDJSONRecords writes one compact JSON object per line
+func writeNDJSONRecords(w io.Writer, headers []string, rows [][]interface{}) error {
+	var buf bytes.Buffer
+	for _, row := range rows {
+		if err := appendJSONRecord(&buf, headers, row, ""); err != nil {
+			return err
+		}
+		buf.WriteString("\n")
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// recordHeaders turns a header row into object keys, naming empty cells after their column
+func recordHeaders(row []interface{}) []string {
+	headers := make([]string, len(row))
+	for i, cell := range row {
+		headers[i] = helpers.CellString(cell)
+		if headers[i] == "" {
+			headers[i] = helpers.ColumnToLetters(i)
+		}
+	}
+	return headers
+}
+
+// writeJSONRecords writes rows as an indented JSON array of objects whose keys follow the header order
+func writeJSONRecords(w io.Writer, headers []string, rows [][]interface{}) error {
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	for i, row := range rows {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  ")
+		if err := appendJSONRecord(&buf, headers, row, "  "); err != nil {
+			return err
+		}
+	}
+	if len(rows) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("]\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// appendJSONRecord encodes one row as a JSON object; missing trailing cells become empty strings
+func appendJSONRecord(buf *bytes.Buffer, headers []string, row []interface{}, indent string) error {
+	buf.WriteString("{")
+	for i, header := range headers {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		if indent != "" {
+			buf.WriteString("\n" + indent + "  ")
+		}
+
+		var value interface{} = ""
+		if i < len(row) {
+			value = row[i]
+		}
+
+		key, err := json.Marshal(header)
+		if err != nil {
+			return err
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		buf.Write(key)
+		buf.WriteString(":")
+		if indent != "" {
+			buf.WriteString(" ")
+		}
+		buf.Write(encoded)
+	}
+	if indent != "" && len(headers) > 0 {
+		buf.WriteString("\n" + indent)
+	}
+	buf.WriteString("}")
+	return nil
+}
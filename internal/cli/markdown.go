@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+var exportMarkdownOutput string
+
+var exportMarkdownCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export-markdown <spreadsheet-id> <sheet-name> [range]",
+		Short: "Export a sheet or range as a GitHub-flavored Markdown table",
+		Long: `Export a sheet or range as a GitHub-flavored Markdown table.
+
+The first row is the header. Numeric columns are right-aligned, boolean
+columns centered and everything else left-aligned.`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: runExportMarkdown,
+	}
+	cmd.Flags().StringVarP(&exportMarkdownOutput, "output", "o", "", "Write to a file instead of stdout")
+	return cmd
+}()
+
+func runExportMarkdown(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	rangeA1 := ""
+	if len(args) == 3 {
+		rangeA1 = args[2]
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetTitle, err := helpers.ResolveSheetTitle(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	fullRange := helpers.SheetRange(sheetTitle, rangeA1)
+	formatted, err := service.Spreadsheets.Values.Get(spreadsheetID, fullRange).Do()
+	if err != nil {
+		return fmt.Errorf("unable to get sheet data: %w", err)
+	}
+	if len(formatted.Values) == 0 {
+		return fmt.Errorf("no data in %s", fullRange)
+	}
+
+	// Unformatted values carry the cell types used for alignment
+	typed, err := service.Spreadsheets.Values.Get(spreadsheetID, fullRange).
+		ValueRenderOption(ValueRenderUnformatted).Do()
+	if err != nil {
+		return fmt.Errorf("unable to get sheet data: %w", err)
+	}
+
+	table := markdownTable(formatted.Values, typed.Values)
+
+	if exportMarkdownOutput == "" {
+		fmt.Print(table)
+		return nil
+	}
+
+	if err := os.WriteFile(exportMarkdownOutput, []byte(table), 0644); err != nil {
+		return fmt.Errorf("unable to write Markdown file: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status": "success",
+		"file":   exportMarkdownOutput,
+		"rows":   len(formatted.Values) - 1,
+	})
+}
+
+// markdownTable renders rows as a Markdown table, aligning columns from the typed values
+func markdownTable(values, typed [][]interface{}) string {
+	width := 0
+	for _, row := range values {
+		width = max(width, len(row))
+	}
+
+	var b strings.Builder
+	writeRow := func(row []interface{}) {
+		b.WriteString("|")
+		for i := 0; i < width; i++ {
+			cell := ""
+			if i < len(row) {
+				cell = markdownEscape(helpers.CellString(row[i]))
+			}
+			b.WriteString(" " + cell + " |")
+		}
+		b.WriteString("\n")
+	}
+
+	writeRow(values[0])
+	b.WriteString("|")
+	for i := 0; i < width; i++ {
+		switch columnKind(typed, i) {
+		case "number":
+			b.WriteString(" ---: |")
+		case "bool":
+			b.WriteString(" :---: |")
+		default:
+			b.WriteString(" --- |")
+		}
+	}
+	b.WriteString("\n")
+	for _, row := range values[1:] {
+		writeRow(row)
+	}
+
+	return b.String()
+}
+
+func markdownEscape(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	value = strings.ReplaceAll(value, "\r\n", "<br>")
+	return strings.ReplaceAll(value, "\n", "<br>")
+}
+
+var (
+	importMarkdownParse     bool
+	importMarkdownStartCell string
+)
+
+var importMarkdownCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-markdown <spreadsheet-id> <sheet-name> <file.md|->",
+		Short: "Import the first Markdown table of a file (or stdin) into a sheet",
+		Args:  cobra.ExactArgs(3),
+		RunE:  runImportMarkdown,
+	}
+	cmd.Flags().BoolVar(&importMarkdownParse, "parse", false, "Parse cells as if typed in the UI (numbers, dates, formulas)")
+	cmd.Flags().StringVar(&importMarkdownStartCell, "start", DefaultStartCell, "Starting cell")
+	return cmd
+}()
+
+func runImportMarkdown(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	path := args[2]
+
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read Markdown: %w", err)
+	}
+
+	values := parseMarkdownTable(string(data))
+	if len(values) == 0 {
+		return fmt.Errorf("no Markdown table found in %s", path)
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetTitle, err := helpers.ResolveSheetTitle(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	valueRange := &sheets.ValueRange{
+		Values: values,
+	}
+
+	inputMode := ValueInputModeRaw
+	if importMarkdownParse {
+		inputMode = ValueInputModeFormula
+	}
+
+	_, err = service.Spreadsheets.Values.Update(
+		spreadsheetID,
+		helpers.SheetRange(sheetTitle, importMarkdownStartCell),
+		valueRange,
+	).ValueInputOption(inputMode).Do()
+
+	if err != nil {
+		return fmt.Errorf("unable to import Markdown table: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status": "success",
+		"rows":   len(values),
+	})
+}
+
+// parseMarkdownTable extracts the first pipe table of a Markdown document, dropping the alignment row
+func parseMarkdownTable(doc string) [][]interface{} {
+	var values [][]interface{}
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "|") {
+			if len(values) > 0 {
+				break
+			}
+			continue
+		}
+
+		cells := splitMarkdownRow(line)
+		if isMarkdownSeparator(cells) {
+			continue
+		}
+
+		row := make([]interface{}, len(cells))
+		for i, cell := range cells {
+			row[i] = strings.ReplaceAll(cell, "<br>", "\n")
+		}
+		values = append(values, row)
+	}
+	return values
+}
+
+// splitMarkdownRow splits "| a | b \| c |" into cells, honoring escaped pipes
+func splitMarkdownRow(line string) []string {
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, "\\|") {
+		line = strings.TrimSuffix(line, "|")
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+func isMarkdownSeparator(cells []string) bool {
+	for _, cell := range cells {
+		trimmed := strings.Trim(cell, ":")
+		if trimmed == "" || strings.Trim(trimmed, "-") != "" {
+			return false
+		}
+	}
+	return true
+}
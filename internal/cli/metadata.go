@@ -0,0 +1,320 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+var (
+	setMetadataSheet      string
+	setMetadataRows       string
+	setMetadataColumns    string
+	setMetadataVisibility string
+)
+
+var setMetadataCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-metadata <spreadsheet-id> <key> <value>",
+		Short: "Tag the spreadsheet, a sheet, rows or columns with developer metadata",
+		Long: `Tag the spreadsheet, a sheet, rows or columns with developer metadata.
+
+Metadata follows what it is attached to: it stays on the same rows when rows
+are inserted above, and on the same sheet when the sheet is renamed or moved,
+so it can be found again with search-metadata. Without --sheet, the metadata
+is attached to the spreadsheet itself.`,
+		Args: cobra.ExactArgs(3),
+		RunE: runSetMetadata,
+	}
+	cmd.Flags().StringVar(&setMetadataSheet, "sheet", "", "Attach to this sheet")
+	cmd.Flags().StringVar(&setMetadataRows, "rows", "", "Attach to rows of --sheet (e.g. 2:10 or 5)")
+	cmd.Flags().StringVar(&setMetadataColumns, "columns", "", "Attach to columns of --sheet (e.g. B:D or C)")
+	cmd.Flags().StringVar(&setMetadataVisibility, "visibility", "DOCUMENT", "DOCUMENT (visible to all editors) or PROJECT (only this OAuth project)")
+	cmd.MarkFlagsMutuallyExclusive("rows", "columns")
+	return cmd
+}()
+
+func runSetMetadata(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	if (setMetadataRows != "" || setMetadataColumns != "") && setMetadataSheet == "" {
+		return fmt.Errorf("--rows and --columns require --sheet")
+	}
+	if setMetadataVisibility != "DOCUMENT" && setMetadataVisibility != "PROJECT" {
+		return fmt.Errorf("invalid --visibility '%s': expected DOCUMENT or PROJECT", setMetadataVisibility)
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	location, err := metadataLocation(service, spreadsheetID, setMetadataSheet, setMetadataRows, setMetadataColumns)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.Request{
+		CreateDeveloperMetadata: &sheets.CreateDeveloperMetadataRequest{
+			DeveloperMetadata: &sheets.DeveloperMetadata{
+				MetadataKey:   args[1],
+				MetadataValue: args[2],
+				Location:      location,
+				Visibility:    setMetadataVisibility,
+			},
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	resp, err := service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to set metadata: %w", err)
+	}
+
+	result := map[string]interface{}{
+		"status": "success",
+	}
+	if len(resp.Replies) > 0 && resp.Replies[0].CreateDeveloperMetadata != nil && resp.Replies[0].CreateDeveloperMetadata.DeveloperMetadata != nil {
+		result["metadata"] = metadataEntry(resp.Replies[0].CreateDeveloperMetadata.DeveloperMetadata)
+	}
+
+	return helpers.PrintJSON(result)
+}
+
+// metadataLocation builds the location of new metadata: the spreadsheet, a sheet, or rows or columns of a sheet
+func metadataLocation(service *sheets.Service, spreadsheetID, sheetRef, rows, columns string) (*sheets.DeveloperMetadataLocation, error) {
+	if sheetRef == "" {
+		return &sheets.DeveloperMetadataLocation{Spreadsheet: true}, nil
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetRef)
+	if err != nil {
+		return nil, err
+	}
+
+	var dimensionRange *sheets.DimensionRange
+	switch {
+	case rows != "":
+		dimensionRange, err = parseDimensionRange(sheetID, MajorDimensionRows, rows)
+	case columns != "":
+		dimensionRange, err = parseDimensionRange(sheetID, MajorDimensionColumns, columns)
+	default:
+		// SheetId 0 is the zero value and has to be sent explicitly
+		return &sheets.DeveloperMetadataLocation{SheetId: sheetID, ForceSendFields: []string{"SheetId"}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &sheets.DeveloperMetadataLocation{DimensionRange: dimensionRange}, nil
+}
+
+var getMetadataCmd = &cobra.Command{
+	Use:   "get-metadata <spreadsheet-id> <metadata-id>",
+	Short: "Print one developer metadata entry by ID",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runGetMetadata,
+}
+
+func runGetMetadata(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	metadataID, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid metadata ID: %s", args[1])
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	metadata, err := service.Spreadsheets.DeveloperMetadata.Get(spreadsheetID, metadataID).Do()
+	if err != nil {
+		return fmt.Errorf("unable to get metadata %d: %w", metadataID, err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":   "success",
+		"metadata": metadataEntry(metadata),
+	})
+}
+
+var (
+	searchMetadataKey   string
+	searchMetadataValue string
+)
+
+var searchMetadataCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search-metadata <spreadsheet-id>",
+		Short: "Find developer metadata by key and/or value",
+		Long: `Find developer metadata by key and/or value, printing where each entry is
+currently attached. Without flags, all metadata visible to this project is listed.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runSearchMetadata,
+	}
+	cmd.Flags().StringVar(&searchMetadataKey, "key", "", "Metadata key")
+	cmd.Flags().StringVar(&searchMetadataValue, "value", "", "Metadata value")
+	return cmd
+}()
+
+func runSearchMetadata(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	matches, err := searchMetadata(service, spreadsheetID, metadataLookup(searchMetadataKey, searchMetadataValue))
+	if err != nil {
+		return err
+	}
+
+	result := make([]map[string]interface{}, 0, len(matches))
+	for _, metadata := range matches {
+		result = append(result, metadataEntry(metadata))
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":   "success",
+		"count":    len(result),
+		"metadata": result,
+	})
+}
+
+// metadataLookup builds a lookup on key and value; with neither, it matches everything at any location
+func metadataLookup(key, value string) *sheets.DeveloperMetadataLookup {
+	lookup := &sheets.DeveloperMetadataLookup{
+		MetadataKey:   key,
+		MetadataValue: value,
+	}
+	if key == "" && value == "" {
+		lookup.LocationMatchingStrategy = "INTERSECTING_LOCATION"
+		lookup.MetadataLocation = &sheets.DeveloperMetadataLocation{Spreadsheet: true}
+	}
+	return lookup
+}
+
+func searchMetadata(service *sheets.Service, spreadsheetID string, lookup *sheets.DeveloperMetadataLookup) ([]*sheets.DeveloperMetadata, error) {
+	resp, err := service.Spreadsheets.DeveloperMetadata.Search(spreadsheetID, &sheets.SearchDeveloperMetadataRequest{
+		DataFilters: []*sheets.DataFilter{{DeveloperMetadataLookup: lookup}},
+	}).Do()
+	if err != nil {
+		return nil, fmt.Errorf("unable to search metadata: %w", err)
+	}
+
+	metadata := make([]*sheets.DeveloperMetadata, 0, len(resp.MatchedDeveloperMetadata))
+	for _, match := range resp.MatchedDeveloperMetadata {
+		metadata = append(metadata, match.DeveloperMetadata)
+	}
+	return metadata, nil
+}
+
+var deleteMetadataKey string
+
+var deleteMetadataCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete-metadata <spreadsheet-id> [metadata-id]",
+		Short: "Delete developer metadata by ID, or every entry with a key",
+		Args:  cobra.RangeArgs(1, 2),
+		RunE:  runDeleteMetadata,
+	}
+	cmd.Flags().StringVar(&deleteMetadataKey, "key", "", "Delete every entry with this key")
+	return cmd
+}()
+
+func runDeleteMetadata(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	lookup := &sheets.DeveloperMetadataLookup{}
+	switch {
+	case len(args) == 2 && deleteMetadataKey != "":
+		return fmt.Errorf("provide either a metadata ID or --key, not both")
+	case len(args) == 2:
+		metadataID, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid metadata ID: %s", args[1])
+		}
+		lookup.MetadataId = metadataID
+	case deleteMetadataKey != "":
+		lookup.MetadataKey = deleteMetadataKey
+	default:
+		return fmt.Errorf("a metadata ID or --key is required")
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.Request{
+		DeleteDeveloperMetadata: &sheets.DeleteDeveloperMetadataRequest{
+			DataFilter: &sheets.DataFilter{DeveloperMetadataLookup: lookup},
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	resp, err := service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to delete metadata: %w", err)
+	}
+
+	deleted := 0
+	if len(resp.Replies) > 0 && resp.Replies[0].DeleteDeveloperMetadata != nil {
+		deleted = len(resp.Replies[0].DeleteDeveloperMetadata.DeletedDeveloperMetadata)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":  "success",
+		"deleted": deleted,
+	})
+}
+
+// metadataEntry flattens developer metadata and its location for output
+func metadataEntry(metadata *sheets.DeveloperMetadata) map[string]interface{} {
+	entry := map[string]interface{}{
+		"id":         metadata.MetadataId,
+		"key":        metadata.MetadataKey,
+		"value":      metadata.MetadataValue,
+		"visibility": metadata.Visibility,
+	}
+
+	location := metadata.Location
+	switch {
+	case location == nil:
+	case location.DimensionRange != nil:
+		dr := location.DimensionRange
+		entry["location"] = "dimension"
+		entry["sheet_id"] = dr.SheetId
+		if dr.Dimension == MajorDimensionColumns {
+			entry["columns"] = helpers.ColumnToLetters(int(dr.StartIndex)) + ":" + helpers.ColumnToLetters(int(dr.EndIndex-1))
+		} else {
+			entry["rows"] = fmt.Sprintf("%d:%d", dr.StartIndex+1, dr.EndIndex)
+		}
+	case location.LocationType == "SHEET":
+		entry["location"] = "sheet"
+		entry["sheet_id"] = location.SheetId
+	default:
+		entry["location"] = "spreadsheet"
+	}
+
+	return entry
+}
@@ -0,0 +1,326 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+var addNoteCmd = &cobra.Command{
+	Use:   "add-note <spreadsheet-id> <sheet-name> <cell> <note>",
+	Short: "Add a note to a cell",
+	Args:  cobra.ExactArgs(4),
+	RunE:  runAddNote,
+}
+
+func runAddNote(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	cell := args[2]
+	note := args[3]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	col, row, err := helpers.A1ToGrid(cell)
+	if err != nil {
+		return err
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{noteRequest(sheetID, col, row, note)},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to add note: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":      "success",
+		"cell":        cell,
+		"note_length": len(note),
+	})
+}
+
+// noteRequest sets the note of one cell; an empty note removes it
+func noteRequest(sheetID int64, col, row int, note string) *sheets.Request {
+	return &sheets.Request{
+		UpdateCells: &sheets.UpdateCellsRequest{
+			Range: &sheets.GridRange{
+				SheetId:          sheetID,
+				StartRowIndex:    int64(row),
+				EndRowIndex:      int64(row + 1),
+				StartColumnIndex: int64(col),
+				EndColumnIndex:   int64(col + 1),
+			},
+			Rows: []*sheets.RowData{
+				{
+					Values: []*sheets.CellData{
+						{Note: note},
+					},
+				},
+			},
+			Fields: "note",
+		},
+	}
+}
+
+var (
+	importNotesDelimiter  string
+	importNotesSkipHeader bool
+)
+
+var importNotesCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import-notes <spreadsheet-id> <sheet-name> <notes.csv|->",
+		Short: "Set many cell notes at once from a cell,note CSV file",
+		Long: `Set many cell notes at once from a CSV file of cell,note pairs (e.g. B3,"Check this").
+
+All notes are written in a single batch update. An empty note removes the
+note of that cell.`,
+		Args: cobra.ExactArgs(3),
+		RunE: runImportNotes,
+	}
+	cmd.Flags().StringVar(&importNotesDelimiter, "delimiter", "", "Field delimiter (default: tab for .tsv files, comma otherwise)")
+	cmd.Flags().BoolVar(&importNotesSkipHeader, "skip-header", false, "Ignore the first line of the file")
+	return cmd
+}()
+
+func runImportNotes(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	csvPath := args[2]
+
+	dialect, err := newCSVDialect(importNotesDelimiter, csvPath)
+	if err != nil {
+		return err
+	}
+	values, err := readCSV(csvPath, dialect)
+	if err != nil {
+		return err
+	}
+	if importNotesSkipHeader && len(values) > 0 {
+		values = values[1:]
+	}
+	if len(values) == 0 {
+		return fmt.Errorf("no notes in %s", csvPath)
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	firstLine := 1
+	if importNotesSkipHeader {
+		firstLine = 2
+	}
+
+	requests := make([]*sheets.Request, 0, len(values))
+	for i, record := range values {
+		if len(record) < 2 {
+			return fmt.Errorf("line %d: expected cell,note", firstLine+i)
+		}
+		cell := strings.ToUpper(strings.TrimSpace(helpers.CellString(record[0])))
+		col, row, err := helpers.A1ToGrid(cell)
+		if err != nil || col < 0 || row < 0 {
+			return fmt.Errorf("line %d: invalid cell reference '%s'", firstLine+i, cell)
+		}
+		requests = append(requests, noteRequest(sheetID, col, row, helpers.CellString(record[1])))
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: requests,
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to import notes: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status": "success",
+		"notes":  len(requests),
+	})
+}
+
+var getNoteCmd = &cobra.Command{
+	Use:   "get-note <spreadsheet-id> <sheet-name> <cell>",
+	Short: "Print the note of a cell",
+	Args:  cobra.ExactArgs(3),
+	RunE:  runGetNote,
+}
+
+func runGetNote(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	cell := args[2]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetTitle, err := helpers.ResolveSheetTitle(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	notes, err := getNotes(service, spreadsheetID, helpers.SheetRange(sheetTitle, cell))
+	if err != nil {
+		return err
+	}
+
+	note := ""
+	if len(notes) > 0 {
+		note = notes[0]["note"]
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status": "success",
+		"cell":   cell,
+		"note":   note,
+	})
+}
+
+var listNotesCmd = &cobra.Command{
+	Use:   "list-notes <spreadsheet-id> [sheet-name]",
+	Short: "List every cell note of a spreadsheet or sheet",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  runListNotes,
+}
+
+func runListNotes(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	var ranges []string
+	if len(args) == 2 {
+		sheetTitle, err := helpers.ResolveSheetTitle(service, spreadsheetID, args[1])
+		if err != nil {
+			return err
+		}
+		ranges = append(ranges, helpers.SheetRange(sheetTitle, ""))
+	}
+
+	notes, err := getNotes(service, spreadsheetID, ranges...)
+	if err != nil {
+		return err
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status": "success",
+		"count":  len(notes),
+		"notes":  notes,
+	})
+}
+
+// getNotes scans the grid data of the given ranges (all sheets when none) and returns the non-empty notes
+func getNotes(service *sheets.Service, spreadsheetID string, ranges ...string) ([]map[string]string, error) {
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetID).
+		Ranges(ranges...).
+		IncludeGridData(true).
+		Fields("sheets(properties(title),data(startRow,startColumn,rowData(values(note))))").
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("unable to get notes: %w", err)
+	}
+
+	notes := []map[string]string{}
+	for _, sheet := range spreadsheet.Sheets {
+		for _, data := range sheet.Data {
+			for r, row := range data.RowData {
+				for c, value := range row.Values {
+					if value.Note == "" {
+						continue
+					}
+					notes = append(notes, map[string]string{
+						"sheet": sheet.Properties.Title,
+						"cell":  helpers.GridToA1(int(data.StartColumn)+c, int(data.StartRow)+r),
+						"note":  value.Note,
+					})
+				}
+			}
+		}
+	}
+
+	return notes, nil
+}
+
+var deleteNoteCmd = &cobra.Command{
+	Use:   "delete-note <spreadsheet-id> <sheet-name> <cell-or-range>",
+	Short: "Remove the notes of a cell or range",
+	Args:  cobra.ExactArgs(3),
+	RunE:  runDeleteNote,
+}
+
+func runDeleteNote(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	rangeA1 := args[2]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	gridRange, err := helpers.ParseGridRange(sheetID, rangeA1)
+	if err != nil {
+		return err
+	}
+
+	// No rows with a "note" mask clears the field on every cell of the range
+	req := &sheets.Request{
+		UpdateCells: &sheets.UpdateCellsRequest{
+			Range:  gridRange,
+			Fields: "note",
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to delete note: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]string{
+		"status": "success",
+		"range":  rangeA1,
+	})
+}
@@ -0,0 +1,273 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+// pivotSpec is the declarative JSON format accepted by create-pivot.
+// Columns are sheet column letters and must lie inside the source range.
+type pivotSpec struct {
+	Source      string            `json:"source"`
+	Rows        []pivotGroupSpec  `json:"rows"`
+	Columns     []pivotGroupSpec  `json:"columns"`
+	Values      []pivotValueSpec  `json:"values"`
+	Filters     []pivotFilterSpec `json:"filters"`
+	ValueLayout string            `json:"value_layout"`
+}
+
+type pivotGroupSpec struct {
+	Column string `json:"column"`
+	Label  string `json:"label"`
+	Sort   string `json:"sort"`
+	Totals *bool  `json:"totals"`
+}
+
+type pivotValueSpec struct {
+	Column   string `json:"column"`
+	Function string `json:"function"`
+	Name     string `json:"name"`
+	Formula  string `json:"formula"`
+}
+
+type pivotFilterSpec struct {
+	Column        string   `json:"column"`
+	VisibleValues []string `json:"visible_values"`
+	Condition     string   `json:"condition"`
+	Values        []string `json:"values"`
+}
+
+var createPivotSpec string
+
+var createPivotCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create-pivot <spreadsheet-id> <sheet-name> <anchor-cell>",
+		Short: "Create a pivot table from a JSON spec",
+		Long: `Create a pivot table anchored at a cell from a JSON spec file ("-" reads stdin).
+
+Example spec:
+  {
+    "source": "Data!A1:E500",
+    "rows": [{"column": "A", "sort": "ASCENDING"}],
+    "columns": [{"column": "B", "totals": false}],
+    "values": [{"column": "D", "function": "SUM", "name": "Total"}],
+    "filters": [{"column": "C", "visible_values": ["EU", "US"]},
+                {"column": "D", "condition": ">", "values": ["0"]}],
+    "value_layout": "HORIZONTAL"
+  }
+
+Columns are sheet column letters inside the source range. Functions are API
+summarize functions (SUM, COUNTA, AVERAGE, MAX, MIN, COUNTUNIQUE...), or CUSTOM
+with a "formula". Filter conditions accept the same shorthands as conditional-format.`,
+		Args: cobra.ExactArgs(3),
+		RunE: runCreatePivot,
+	}
+	cmd.Flags().StringVar(&createPivotSpec, "spec", "", "Path to the pivot JSON spec, or - for stdin")
+	_ = cmd.MarkFlagRequired("spec")
+	return cmd
+}()
+
+func runCreatePivot(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	anchorCell := args[2]
+
+	var data []byte
+	var err error
+	if createPivotSpec == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(createPivotSpec)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read pivot spec: %w", err)
+	}
+
+	var spec pivotSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return fmt.Errorf("invalid pivot spec: %w", err)
+	}
+	if spec.Source == "" {
+		return fmt.Errorf("pivot spec requires a source range")
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	spreadsheet, err := helpers.GetSpreadsheetSheets(service, spreadsheetID)
+	if err != nil {
+		return fmt.Errorf("unable to get spreadsheet: %w", err)
+	}
+
+	anchorSheet, err := helpers.FindSheet(spreadsheet, sheetName)
+	if err != nil {
+		return err
+	}
+
+	source, err := resolveSheetGridRange(spreadsheet, spec.Source)
+	if err != nil {
+		return err
+	}
+
+	pivotTable, err := buildPivotTable(&spec, source)
+	if err != nil {
+		return err
+	}
+
+	col, row, err := helpers.A1ToGrid(anchorCell)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.Request{
+		UpdateCells: &sheets.UpdateCellsRequest{
+			Start: &sheets.GridCoordinate{
+				SheetId:     anchorSheet.SheetId,
+				RowIndex:    int64(row),
+				ColumnIndex: int64(col),
+			},
+			Rows: []*sheets.RowData{
+				{Values: []*sheets.CellData{{PivotTable: pivotTable}}},
+			},
+			Fields: "pivotTable",
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to create pivot table: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":  "success",
+		"anchor":  anchorCell,
+		"source":  spec.Source,
+		"rows":    len(pivotTable.Rows),
+		"columns": len(pivotTable.Columns),
+		"values":  len(pivotTable.Values),
+	})
+}
+
+// buildPivotTable translates a pivot spec into an API PivotTable over the source range
+func buildPivotTable(spec *pivotSpec, source *sheets.GridRange) (*sheets.PivotTable, error) {
+	offset := func(column string) (int64, error) {
+		col, err := helpers.ColumnIndex(column)
+		if err != nil {
+			return 0, err
+		}
+		if int64(col) < source.StartColumnIndex || (source.EndColumnIndex > 0 && int64(col) >= source.EndColumnIndex) {
+			return 0, fmt.Errorf("column %s is outside the source range %s", column, spec.Source)
+		}
+		return int64(col) - source.StartColumnIndex, nil
+	}
+
+	group := func(g pivotGroupSpec) (*sheets.PivotGroup, error) {
+		off, err := offset(g.Column)
+		if err != nil {
+			return nil, err
+		}
+		sortOrder := strings.ToUpper(g.Sort)
+		if sortOrder == "" {
+			sortOrder = "ASCENDING"
+		}
+		return &sheets.PivotGroup{
+			SourceColumnOffset: off,
+			Label:              g.Label,
+			SortOrder:          sortOrder,
+			ShowTotals:         g.Totals == nil || *g.Totals,
+			ForceSendFields:    []string{"SourceColumnOffset", "ShowTotals"},
+		}, nil
+	}
+
+	pivotTable := &sheets.PivotTable{
+		Source:      source,
+		ValueLayout: strings.ToUpper(spec.ValueLayout),
+	}
+
+	for _, g := range spec.Rows {
+		pg, err := group(g)
+		if err != nil {
+			return nil, err
+		}
+		pivotTable.Rows = append(pivotTable.Rows, pg)
+	}
+
+	for _, g := range spec.Columns {
+		pg, err := group(g)
+		if err != nil {
+			return nil, err
+		}
+		pivotTable.Columns = append(pivotTable.Columns, pg)
+	}
+
+	for _, v := range spec.Values {
+		function := strings.ToUpper(v.Function)
+		if function == "" {
+			function = "SUM"
+		}
+		value := &sheets.PivotValue{
+			SummarizeFunction: function,
+			Name:              v.Name,
+		}
+		if function == "CUSTOM" {
+			if v.Formula == "" {
+				return nil, fmt.Errorf("CUSTOM pivot value requires a formula")
+			}
+			value.Formula = v.Formula
+		} else {
+			off, err := offset(v.Column)
+			if err != nil {
+				return nil, err
+			}
+			value.SourceColumnOffset = off
+			value.ForceSendFields = []string{"SourceColumnOffset"}
+		}
+		pivotTable.Values = append(pivotTable.Values, value)
+	}
+
+	for _, f := range spec.Filters {
+		off, err := offset(f.Column)
+		if err != nil {
+			return nil, err
+		}
+		criteria := &sheets.PivotFilterCriteria{VisibleValues: f.VisibleValues}
+		if f.Condition != "" {
+			conditionType := f.Condition
+			if alias, ok := conditionAliases[strings.ToLower(conditionType)]; ok {
+				conditionType = alias
+			}
+			criteria.Condition = &sheets.BooleanCondition{Type: strings.ToUpper(conditionType)}
+			for _, value := range f.Values {
+				criteria.Condition.Values = append(criteria.Condition.Values, &sheets.ConditionValue{UserEnteredValue: value})
+			}
+		}
+		pivotTable.FilterSpecs = append(pivotTable.FilterSpecs, &sheets.PivotFilterSpec{
+			ColumnOffsetIndex: off,
+			FilterCriteria:    criteria,
+			ForceSendFields:   []string{"ColumnOffsetIndex"},
+		})
+	}
+
+	if len(pivotTable.Values) == 0 && len(pivotTable.Rows) == 0 && len(pivotTable.Columns) == 0 {
+		return nil, fmt.Errorf("pivot spec needs at least one row, column or value")
+	}
+
+	return pivotTable, nil
+}
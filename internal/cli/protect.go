@@ -0,0 +1,391 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+var (
+	protectSheetExceptRanges []string
+	protectSheetEditors      []string
+	protectSheetDescription  string
+	protectSheetWarningOnly  bool
+)
+
+var protectSheetCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "protect-sheet <spreadsheet-id> <sheet-name>",
+		Short: "Protect a whole sheet, optionally leaving some ranges editable",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runProtectSheet,
+	}
+	cmd.Flags().StringSliceVar(&protectSheetExceptRanges, "except-range", nil, "Range left unprotected (repeatable)")
+	cmd.Flags().StringSliceVar(&protectSheetEditors, "editors", nil, "Comma-separated emails allowed to edit")
+	cmd.Flags().StringVar(&protectSheetDescription, "description", "", "Protection description")
+	cmd.Flags().BoolVar(&protectSheetWarningOnly, "warning-only", false, "Warn before edits instead of blocking them")
+	cmd.MarkFlagsMutuallyExclusive("editors", "warning-only")
+	return cmd
+}()
+
+func runProtectSheet(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	var unprotected []*sheets.GridRange
+	for _, rangeA1 := range protectSheetExceptRanges {
+		gridRange, err := helpers.ParseGridRange(sheetID, rangeA1)
+		if err != nil {
+			return err
+		}
+		unprotected = append(unprotected, gridRange)
+	}
+
+	protectedRange := &sheets.ProtectedRange{
+		Range:             &sheets.GridRange{SheetId: sheetID, ForceSendFields: []string{"SheetId"}},
+		Description:       protectSheetDescription,
+		UnprotectedRanges: unprotected,
+		WarningOnly:       protectSheetWarningOnly,
+	}
+	if len(protectSheetEditors) > 0 {
+		protectedRange.Editors = &sheets.Editors{Users: protectSheetEditors}
+	}
+
+	protectedRangeID, err := addProtectedRange(service, spreadsheetID, protectedRange)
+	if err != nil {
+		return fmt.Errorf("unable to protect sheet: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":             "success",
+		"sheet_name":         sheetName,
+		"protected_range_id": protectedRangeID,
+	})
+}
+
+// addProtectedRange sends an AddProtectedRangeRequest and returns the ID of the new protection
+func addProtectedRange(service *sheets.Service, spreadsheetID string, protectedRange *sheets.ProtectedRange) (int64, error) {
+	req := &sheets.Request{
+		AddProtectedRange: &sheets.AddProtectedRangeRequest{
+			ProtectedRange: protectedRange,
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	resp, err := service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return 0, err
+	}
+	if len(resp.Replies) == 0 || resp.Replies[0].AddProtectedRange == nil || resp.Replies[0].AddProtectedRange.ProtectedRange == nil {
+		return 0, fmt.Errorf("no protected range in the response")
+	}
+	return resp.Replies[0].AddProtectedRange.ProtectedRange.ProtectedRangeId, nil
+}
+
+var (
+	protectRangeEditors     []string
+	protectRangeDescription string
+	protectRangeWarningOnly bool
+)
+
+var protectRangeCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "protect-range <spreadsheet-id> <sheet-name> <range>",
+		Short: "Protect a range so only some editors can change it",
+		Long: `Protect a range so only some editors can change it.
+
+Without --editors, only the owner can edit the range. With --warning-only,
+everyone can still edit after confirming a warning (editors do not apply).`,
+		Args: cobra.ExactArgs(3),
+		RunE: runProtectRange,
+	}
+	cmd.Flags().StringSliceVar(&protectRangeEditors, "editors", nil, "Comma-separated emails allowed to edit")
+	cmd.Flags().StringVar(&protectRangeDescription, "description", "", "Protection description")
+	cmd.Flags().BoolVar(&protectRangeWarningOnly, "warning-only", false, "Warn before edits instead of blocking them")
+	cmd.MarkFlagsMutuallyExclusive("editors", "warning-only")
+	return cmd
+}()
+
+func runProtectRange(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	rangeA1 := args[2]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	gridRange, err := helpers.ParseGridRange(sheetID, rangeA1)
+	if err != nil {
+		return err
+	}
+
+	protectedRange := &sheets.ProtectedRange{
+		Range:       gridRange,
+		Description: protectRangeDescription,
+		WarningOnly: protectRangeWarningOnly,
+	}
+	if len(protectRangeEditors) > 0 {
+		protectedRange.Editors = &sheets.Editors{Users: protectRangeEditors}
+	}
+
+	protectedRangeID, err := addProtectedRange(service, spreadsheetID, protectedRange)
+	if err != nil {
+		return fmt.Errorf("unable to protect range: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":             "success",
+		"range":              rangeA1,
+		"protected_range_id": protectedRangeID,
+	})
+}
+
+var (
+	updateProtectionRange       string
+	updateProtectionEditors     []string
+	updateProtectionDescription string
+	updateProtectionWarningOnly bool
+)
+
+var updateProtectionCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update-protection <spreadsheet-id> <protected-range-id>",
+		Short: "Change the range, editors, description or warning mode of a protection",
+		Long: `Change the range, editors, description or warning mode of a protection.
+
+Only the given flags are changed. --editors replaces the whole editor list
+(--editors "" leaves only the owner). --range takes a sheet-qualified range.`,
+		Args: cobra.ExactArgs(2),
+		RunE: runUpdateProtection,
+	}
+	cmd.Flags().StringVar(&updateProtectionRange, "range", "", "New sheet-qualified range (e.g. Sheet1!A1:D10)")
+	cmd.Flags().StringSliceVar(&updateProtectionEditors, "editors", nil, "Comma-separated emails allowed to edit")
+	cmd.Flags().StringVar(&updateProtectionDescription, "description", "", "Protection description")
+	cmd.Flags().BoolVar(&updateProtectionWarningOnly, "warning-only", false, "Warn before edits instead of blocking them")
+	return cmd
+}()
+
+func runUpdateProtection(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	flags := cmd.Flags()
+
+	protectedRangeID, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid protected range ID: %s", args[1])
+	}
+	if updateProtectionWarningOnly && len(updateProtectionEditors) > 0 {
+		return fmt.Errorf("--editors cannot be combined with --warning-only")
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	protectedRange := &sheets.ProtectedRange{ProtectedRangeId: protectedRangeID}
+	var fields []string
+
+	if flags.Changed("range") {
+		sheetName, rangeA1 := helpers.SplitSheetRange(updateProtectionRange)
+		if sheetName == "" {
+			return fmt.Errorf("invalid --range '%s': expected a sheet-qualified range such as Sheet1!A1:D10", updateProtectionRange)
+		}
+		sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+		if err != nil {
+			return err
+		}
+		protectedRange.Range, err = helpers.ParseGridRange(sheetID, rangeA1)
+		if err != nil {
+			return err
+		}
+		fields = append(fields, "range")
+	}
+	if flags.Changed("editors") {
+		protectedRange.Editors = &sheets.Editors{Users: updateProtectionEditors, ForceSendFields: []string{"Users"}}
+		fields = append(fields, "editors")
+	}
+	if flags.Changed("description") {
+		protectedRange.Description = updateProtectionDescription
+		fields = append(fields, "description")
+	}
+	if flags.Changed("warning-only") {
+		protectedRange.WarningOnly = updateProtectionWarningOnly
+		fields = append(fields, "warningOnly")
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("nothing to change: pass at least one of --range, --editors, --description or --warning-only")
+	}
+
+	req := &sheets.Request{
+		UpdateProtectedRange: &sheets.UpdateProtectedRangeRequest{
+			ProtectedRange: protectedRange,
+			Fields:         strings.Join(fields, ","),
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to update protection: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":             "success",
+		"protected_range_id": protectedRangeID,
+		"updated":            fields,
+	})
+}
+
+var listProtectionsCmd = &cobra.Command{
+	Use:   "list-protections <spreadsheet-id> [sheet-name]",
+	Short: "List protected sheets and ranges",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  runListProtections,
+}
+
+func runListProtections(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetID).
+		Fields("sheets(properties(sheetId,title,index),protectedRanges)").Do()
+	if err != nil {
+		return fmt.Errorf("unable to get spreadsheet: %w", err)
+	}
+
+	var onlySheetID *int64
+	if len(args) > 1 {
+		props, err := helpers.FindSheet(spreadsheet, args[1])
+		if err != nil {
+			return err
+		}
+		onlySheetID = &props.SheetId
+	}
+
+	protections := []map[string]interface{}{}
+	for _, sheet := range spreadsheet.Sheets {
+		if onlySheetID != nil && sheet.Properties.SheetId != *onlySheetID {
+			continue
+		}
+		for _, pr := range sheet.ProtectedRanges {
+			protections = append(protections, describeProtectedRange(sheet.Properties.Title, pr))
+		}
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":      "success",
+		"protections": protections,
+	})
+}
+
+func describeProtectedRange(sheetTitle string, pr *sheets.ProtectedRange) map[string]interface{} {
+	entry := map[string]interface{}{
+		"protected_range_id": pr.ProtectedRangeId,
+		"sheet_name":         sheetTitle,
+		"description":        pr.Description,
+		"warning_only":       pr.WarningOnly,
+		"whole_sheet":        isWholeSheet(pr.Range),
+	}
+	if pr.NamedRangeId != "" {
+		entry["named_range_id"] = pr.NamedRangeId
+	}
+	if pr.Range != nil && !isWholeSheet(pr.Range) {
+		entry["range"] = helpers.GridRangeToA1(pr.Range)
+	}
+	if pr.Editors != nil {
+		entry["editors"] = pr.Editors.Users
+	}
+	var unprotected []string
+	for _, gr := range pr.UnprotectedRanges {
+		unprotected = append(unprotected, helpers.GridRangeToA1(gr))
+	}
+	if len(unprotected) > 0 {
+		entry["unprotected_ranges"] = unprotected
+	}
+	return entry
+}
+
+func isWholeSheet(gr *sheets.GridRange) bool {
+	return gr != nil && gr.StartRowIndex == 0 && gr.EndRowIndex == 0 &&
+		gr.StartColumnIndex == 0 && gr.EndColumnIndex == 0
+}
+
+var removeProtectionCmd = &cobra.Command{
+	Use:   "remove-protection <spreadsheet-id> <protected-range-id>",
+	Short: "Remove a sheet or range protection",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runRemoveProtection,
+}
+
+func runRemoveProtection(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	protectedRangeID, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid protected range ID: %s", args[1])
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.Request{
+		DeleteProtectedRange: &sheets.DeleteProtectedRangeRequest{
+			ProtectedRangeId: protectedRangeID,
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to remove protection: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":             "success",
+		"protected_range_id": protectedRangeID,
+	})
+}
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+var (
+	copyRangeMove      bool
+	copyRangePasteType string
+)
+
+var copyRangeCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "copy-range <spreadsheet-id> <source-range> <destination-range>",
+		Short: "Copy (or move) a range, e.g. 'Sheet1!A1:C10' to 'Sheet2!E1'",
+		Args:  cobra.ExactArgs(3),
+		RunE:  runCopyRange,
+	}
+	cmd.Flags().BoolVar(&copyRangeMove, "move", false, "Cut and paste instead of copying")
+	cmd.Flags().StringVar(&copyRangePasteType, "paste-type", "NORMAL", "What to paste (NORMAL, VALUES, FORMAT, FORMULA, NO_BORDERS, DATA_VALIDATION, CONDITIONAL_FORMATTING)")
+	return cmd
+}()
+
+func runCopyRange(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	spreadsheet, err := helpers.GetSpreadsheetSheets(service, spreadsheetID)
+	if err != nil {
+		return fmt.Errorf("unable to get spreadsheet: %w", err)
+	}
+
+	source, err := resolveSheetGridRange(spreadsheet, args[1])
+	if err != nil {
+		return err
+	}
+
+	destination, err := resolveSheetGridRange(spreadsheet, args[2])
+	if err != nil {
+		return err
+	}
+
+	pasteType := strings.ToUpper(copyRangePasteType)
+	if !strings.HasPrefix(pasteType, "PASTE_") {
+		pasteType = "PASTE_" + pasteType
+	}
+
+	req := &sheets.Request{}
+	if copyRangeMove {
+		req.CutPaste = &sheets.CutPasteRequest{
+			Source: source,
+			Destination: &sheets.GridCoordinate{
+				SheetId:     destination.SheetId,
+				RowIndex:    destination.StartRowIndex,
+				ColumnIndex: destination.StartColumnIndex,
+			},
+			PasteType: pasteType,
+		}
+	} else {
+		req.CopyPaste = &sheets.CopyPasteRequest{
+			Source:      source,
+			Destination: destination,
+			PasteType:   pasteType,
+		}
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to paste range: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":      "success",
+		"source":      args[1],
+		"destination": args[2],
+		"moved":       copyRangeMove,
+		"paste_type":  pasteType,
+	})
+}
+
+// resolveSheetGridRange converts a sheet-qualified range ("Sheet1!A1:B2") into a GridRange.
+// The sheet part accepts the same references as <sheet-name> arguments.
+func resolveSheetGridRange(spreadsheet *sheets.Spreadsheet, value string) (*sheets.GridRange, error) {
+	sheetRef, rangeA1 := helpers.SplitSheetRange(value)
+	if sheetRef == "" {
+		return nil, fmt.Errorf("range '%s' must include a sheet name (e.g. Sheet1!A1:B2)", value)
+	}
+
+	props, err := helpers.FindSheet(spreadsheet, sheetRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return helpers.ParseGridRange(props.SheetId, rangeA1)
+}
+
+var (
+	fillDownLastRow     int
+	fillDownAlternating bool
+)
+
+var fillDownCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fill-down <spreadsheet-id> <sheet-name> <source-range>",
+		Short: "Extend a row (e.g. a formula in C2:E2) down to the last data row",
+		Long: `Extend a source row down with autofill, adjusting formulas and series like the UI drag handle.
+
+Without --last-row, the last populated row of the sheet is detected automatically.`,
+		Args: cobra.ExactArgs(3),
+		RunE: runFillDown,
+	}
+	cmd.Flags().IntVar(&fillDownLastRow, "last-row", 0, "Last 1-based row to fill (default: last populated row)")
+	cmd.Flags().BoolVar(&fillDownAlternating, "alternating", false, "Treat the source as alternating series data")
+	return cmd
+}()
+
+func runFillDown(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	sourceA1 := args[2]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheet, err := helpers.GetSheetProperties(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	source, err := helpers.ParseGridRange(sheet.SheetId, sourceA1)
+	if err != nil {
+		return err
+	}
+
+	lastRow := int64(fillDownLastRow)
+	if lastRow == 0 {
+		resp, err := service.Spreadsheets.Values.Get(spreadsheetID, helpers.SheetRange(sheet.Title, "")).Do()
+		if err != nil {
+			return fmt.Errorf("unable to detect last row: %w", err)
+		}
+		lastRow = int64(len(resp.Values))
+	}
+
+	fillLength := lastRow - source.EndRowIndex
+	if fillLength <= 0 {
+		return fmt.Errorf("nothing to fill: last row %d is not below the source range %s", lastRow, sourceA1)
+	}
+
+	req := &sheets.Request{
+		AutoFill: &sheets.AutoFillRequest{
+			SourceAndDestination: &sheets.SourceAndDestination{
+				Source:     source,
+				Dimension:  MajorDimensionRows,
+				FillLength: fillLength,
+			},
+			UseAlternateSeries: fillDownAlternating,
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to fill down: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":      "success",
+		"source":      sourceA1,
+		"last_row":    lastRow,
+		"rows_filled": fillLength,
+	})
+}
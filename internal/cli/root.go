@@ -1,22 +1,181 @@
 package cli
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
 
 var RootCmd = &cobra.Command{
 	Use:   "spreadsheet-manager",
 	Short: "Google Sheets Spreadsheet Manager",
 	Long:  "Comprehensive spreadsheet operations: create, format, style, import/export",
+
+	PersistentPreRunE: validateRootFlags,
 }
 
+var rootQuotaReport bool
+
 func init() {
+	RootCmd.PersistentFlags().StringVar(&auth.CredentialsFilePath, "credentials", "", "OAuth client credentials file (default: $"+auth.CredentialsEnv+" or "+auth.CredentialsFile+" in the config directory)")
+	RootCmd.PersistentFlags().StringVar(&auth.TokenFilePath, "token", "", "OAuth token file (default: $"+auth.TokenEnv+" or "+auth.TokenFile+" in the config directory)")
+	RootCmd.PersistentFlags().StringVar(&auth.ServiceAccountFile, "service-account", "", "Service account key file (default: $"+auth.ServiceAccountEnv+", or $"+auth.ApplicationCredentialsEnv+" if it is a service account key)")
+	RootCmd.PersistentFlags().StringVar(&auth.Impersonate, "impersonate", "", "With a service account, act as this Workspace user through domain-wide delegation (default: $"+auth.ImpersonateEnv+")")
+	RootCmd.PersistentFlags().DurationVar(&helpers.CacheTTL, "cache-ttl", 0, "Keep spreadsheet metadata (sheet names, IDs, grid sizes) on disk for this long, e.g. 10m (default: $"+helpers.CacheTTLEnv+", or memory only)")
+	RootCmd.PersistentFlags().IntVar(&auth.MaxRetries, "max-retries", auth.DefaultMaxRetries, "Retries of a request rejected with 429, 5xx or a rate limit error (0 disables)")
+	RootCmd.PersistentFlags().DurationVar(&auth.RetryMaxWait, "retry-max-wait", auth.DefaultRetryMaxWait, "Longest wait between two retries")
+	RootCmd.PersistentFlags().IntVar(&auth.RateLimit, "rate-limit", 0, "Most API requests sent per minute, retries included (0 for no limit)")
+	RootCmd.PersistentFlags().BoolVar(&rootQuotaReport, "quota-report", false, "Print the API requests sent, by API and read or write, to stderr when the command ends")
+	RootCmd.PersistentFlags().BoolVarP(&helpers.Quiet, "quiet", "q", false, "Do not report progress of long imports, exports and batch plans, or retries, on stderr")
+	RootCmd.PersistentFlags().StringVar(&auth.Profile, "profile", "", "Auth profile with its own token (default: $"+auth.ProfileEnv+", default_profile in "+auth.ConfigFile+", or \""+auth.DefaultProfile+"\")")
+
+	RootCmd.AddCommand(addBandingCmd)
+	RootCmd.AddCommand(addChartCmd)
+	RootCmd.AddCommand(addCommentCmd)
 	RootCmd.AddCommand(addDataCmd)
+	RootCmd.AddCommand(addFilterViewCmd)
 	RootCmd.AddCommand(addNoteCmd)
+	RootCmd.AddCommand(addSparklinesCmd)
+	RootCmd.AddCommand(addTotalsRowCmd)
+	RootCmd.AddCommand(authCmd)
+	RootCmd.AddCommand(autoResizeColumnsCmd)
+	RootCmd.AddCommand(batchCmd)
+	RootCmd.AddCommand(clearCacheCmd)
+	RootCmd.AddCommand(clearFilterCmd)
+	RootCmd.AddCommand(conditionalFormatCmd)
+	RootCmd.AddCommand(copyCmd)
+	RootCmd.AddCommand(copyRangeCmd)
+	RootCmd.AddCommand(copySheetToCmd)
 	RootCmd.AddCommand(createCmd)
+	RootCmd.AddCommand(createPivotCmd)
 	RootCmd.AddCommand(createSheetCmd)
+	RootCmd.AddCommand(deleteBandingCmd)
+	RootCmd.AddCommand(deleteChartCmd)
+	RootCmd.AddCommand(deleteCmd)
+	RootCmd.AddCommand(deleteColumnsCmd)
+	RootCmd.AddCommand(deleteFilterViewCmd)
+	RootCmd.AddCommand(deleteMetadataCmd)
+	RootCmd.AddCommand(deleteNoteCmd)
+	RootCmd.AddCommand(deleteRowsCmd)
+	RootCmd.AddCommand(deleteRowsWhereCmd)
+	RootCmd.AddCommand(deleteSheetCmd)
 	RootCmd.AddCommand(exportCSVCmd)
+	RootCmd.AddCommand(exportChartCmd)
+	RootCmd.AddCommand(exportHTMLCmd)
+	RootCmd.AddCommand(exportJSONCmd)
+	RootCmd.AddCommand(exportMarkdownCmd)
+	RootCmd.AddCommand(exportODSCmd)
+	RootCmd.AddCommand(exportPDFCmd)
+	RootCmd.AddCommand(exportXLSXCmd)
+	RootCmd.AddCommand(fillDownCmd)
+	RootCmd.AddCommand(findReplaceCmd)
 	RootCmd.AddCommand(formatCellsCmd)
+	RootCmd.AddCommand(freezeCmd)
+	RootCmd.AddCommand(getFormatCmd)
+	RootCmd.AddCommand(getFormulasCmd)
+	RootCmd.AddCommand(getMetadataCmd)
+	RootCmd.AddCommand(getNoteCmd)
+	RootCmd.AddCommand(hideColumnsCmd)
+	RootCmd.AddCommand(hideRowsCmd)
+	RootCmd.AddCommand(hideSheetCmd)
 	RootCmd.AddCommand(importCSVCmd)
+	RootCmd.AddCommand(importJSONCmd)
+	RootCmd.AddCommand(importMarkdownCmd)
+	RootCmd.AddCommand(importNotesCmd)
+	RootCmd.AddCommand(importXLSXCmd)
+	RootCmd.AddCommand(insertColumnsCmd)
+	RootCmd.AddCommand(insertImageCmd)
+	RootCmd.AddCommand(insertRowsCmd)
+	RootCmd.AddCommand(linkSharingCmd)
+	RootCmd.AddCommand(listBandingCmd)
+	RootCmd.AddCommand(listChartsCmd)
+	RootCmd.AddCommand(listCmd)
+	RootCmd.AddCommand(listCommentsCmd)
+	RootCmd.AddCommand(listFilterViewsCmd)
+	RootCmd.AddCommand(listNotesCmd)
+	RootCmd.AddCommand(listPermissionsCmd)
+	RootCmd.AddCommand(listProtectionsCmd)
 	RootCmd.AddCommand(listSheetsCmd)
+	RootCmd.AddCommand(makeTableCmd)
+	RootCmd.AddCommand(mergeCellsCmd)
+	RootCmd.AddCommand(moveCmd)
+	RootCmd.AddCommand(moveColumnsCmd)
+	RootCmd.AddCommand(moveRowsCmd)
+	RootCmd.AddCommand(moveSheetCmd)
+	RootCmd.AddCommand(protectRangeCmd)
+	RootCmd.AddCommand(protectSheetCmd)
+	RootCmd.AddCommand(publishCmd)
+	RootCmd.AddCommand(removePermissionCmd)
+	RootCmd.AddCommand(removeProtectionCmd)
+	RootCmd.AddCommand(renameCmd)
 	RootCmd.AddCommand(renameSheetCmd)
+	RootCmd.AddCommand(replyCommentCmd)
+	RootCmd.AddCommand(resizeGridCmd)
+	RootCmd.AddCommand(resolveCommentCmd)
+	RootCmd.AddCommand(restoreCmd)
+	RootCmd.AddCommand(searchCmd)
+	RootCmd.AddCommand(searchMetadataCmd)
+	RootCmd.AddCommand(searchSpreadsheetsCmd)
+	RootCmd.AddCommand(setCheckboxCmd)
+	RootCmd.AddCommand(setColumnWidthCmd)
+	RootCmd.AddCommand(setFilterCmd)
+	RootCmd.AddCommand(setHyperlinkCmd)
+	RootCmd.AddCommand(setMetadataCmd)
+	RootCmd.AddCommand(setPropertiesCmd)
+	RootCmd.AddCommand(setRowHeightCmd)
+	RootCmd.AddCommand(setThemeCmd)
+	RootCmd.AddCommand(setValidationCmd)
+	RootCmd.AddCommand(shareCmd)
+	RootCmd.AddCommand(showColumnsCmd)
+	RootCmd.AddCommand(showRowsCmd)
+	RootCmd.AddCommand(showSheetCmd)
+	RootCmd.AddCommand(spreadsheetInfoCmd)
 	RootCmd.AddCommand(styleCellsCmd)
+	RootCmd.AddCommand(textToColumnsCmd)
+	RootCmd.AddCommand(transferOwnershipCmd)
+	RootCmd.AddCommand(trimWhitespaceCmd)
+	RootCmd.AddCommand(unmergeCellsCmd)
+	RootCmd.AddCommand(unpublishCmd)
+	RootCmd.AddCommand(updateChartCmd)
+	RootCmd.AddCommand(updateFilterViewCmd)
+	RootCmd.AddCommand(updateProtectionCmd)
+	RootCmd.AddCommand(upsertRowsCmd)
+
+	authCmd.AddCommand(authListProfilesCmd)
+	authCmd.AddCommand(authLoginCmd)
+	authCmd.AddCommand(authLogoutCmd)
+	authCmd.AddCommand(authStatusCmd)
+}
+
+// validateRootFlags rejects persistent flag values that would defeat their purpose: without a
+// wait, retries of a 429 would hammer the quota they hit
+func validateRootFlags(cmd *cobra.Command, args []string) error {
+	if auth.MaxRetries < 0 {
+		return fmt.Errorf("--max-retries must be 0 or more")
+	}
+	if auth.RetryMaxWait <= 0 {
+		return fmt.Errorf("--retry-max-wait must be positive")
+	}
+	return nil
+}
+
+// PrintQuotaReport prints the API requests of this invocation to stderr when --quota-report is
+// set. It runs after the command, whether it failed or not
+func PrintQuotaReport() {
+	if !rootQuotaReport {
+		return
+	}
+	usage := auth.Usage()
+	_ = helpers.FprintJSON(os.Stderr, map[string]interface{}{
+		"quota": map[string]interface{}{
+			"requests":  usage.Requests,
+			"retries":   usage.Retries,
+			"throttled": usage.Throttled.Round(time.Millisecond).String(),
+		},
+	})
 }
@@ -3,6 +3,9 @@ package cli
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"google.golang.org/api/sheets/v4"
@@ -51,6 +54,421 @@ func runCreateSheet(cmd *cobra.Command, args []string) error {
 	})
 }
 
+// ensureSheets adds every sheet of sheetNames that does not exist yet in one batch update and
+// returns the title each name resolves to along with the names it created. Titles are matched
+// without case, as Sheets does. Prefixed references (gid:, index:) are never created.
+func ensureSheets(service *sheets.Service, spreadsheetID string, sheetNames []string) ([]string, []string, error) {
+	spreadsheet, err := helpers.GetSpreadsheetSheets(service, spreadsheetID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to retrieve spreadsheet: %w", err)
+	}
+
+	titles := make([]string, len(sheetNames))
+	var created []string
+	var requests []*sheets.Request
+	for i, sheetName := range sheetNames {
+		if sheet, err := helpers.FindSheet(spreadsheet, sheetName); err == nil {
+			titles[i] = sheet.Title
+			continue
+		}
+		for _, prefix := range []string{helpers.SheetRefGIDPrefix, helpers.SheetRefIndexPrefix} {
+			if strings.HasPrefix(sheetName, prefix) {
+				return nil, nil, fmt.Errorf("sheet '%s' not found", sheetName)
+			}
+		}
+		sameTitle := func(title string) bool { return strings.EqualFold(title, sheetName) }
+		existing := slices.IndexFunc(spreadsheet.Sheets, func(sheet *sheets.Sheet) bool {
+			return sameTitle(sheet.Properties.Title)
+		})
+		if existing >= 0 {
+			titles[i] = spreadsheet.Sheets[existing].Properties.Title
+			continue
+		}
+		if j := slices.IndexFunc(created, sameTitle); j >= 0 {
+			titles[i] = created[j]
+			continue
+		}
+
+		titles[i] = sheetName
+		created = append(created, sheetName)
+		requests = append(requests, &sheets.Request{
+			AddSheet: &sheets.AddSheetRequest{
+				Properties: &sheets.SheetProperties{
+					Title: sheetName,
+				},
+			},
+		})
+	}
+
+	if len(requests) == 0 {
+		return titles, nil, nil
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: requests,
+	}
+
+	if _, err := service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do(); err != nil {
+		return nil, nil, fmt.Errorf("unable to create sheets: %w", err)
+	}
+	return titles, created, nil
+}
+
+var deleteSheetForce bool
+
+var deleteSheetCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete-sheet <spreadsheet-id> <sheet-name>",
+		Short: "Delete a sheet from the spreadsheet",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runDeleteSheet,
+	}
+	cmd.Flags().BoolVar(&deleteSheetForce, "force", false, "Skip the confirmation prompt")
+	return cmd
+}()
+
+func runDeleteSheet(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	if !deleteSheetForce && !helpers.Confirm(fmt.Sprintf("Delete sheet '%s' and all its data?", sheetName)) {
+		return fmt.Errorf("deletion of sheet '%s' aborted", sheetName)
+	}
+
+	req := &sheets.Request{
+		DeleteSheet: &sheets.DeleteSheetRequest{
+			SheetId: sheetID,
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to delete sheet: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":     "success",
+		"sheet_id":   sheetID,
+		"sheet_name": sheetName,
+	})
+}
+
+var copySheetToName string
+
+var copySheetToCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "copy-sheet-to <spreadsheet-id> <sheet-name> <destination-spreadsheet-id>",
+		Short: "Copy a sheet into another spreadsheet",
+		Args:  cobra.ExactArgs(3),
+		RunE:  runCopySheetTo,
+	}
+	cmd.Flags().StringVar(&copySheetToName, "name", "", "Rename the copied sheet (default \"Copy of <sheet-name>\")")
+	return cmd
+}()
+
+func runCopySheetTo(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	destinationID := args[2]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	copyReq := &sheets.CopySheetToAnotherSpreadsheetRequest{
+		DestinationSpreadsheetId: destinationID,
+	}
+
+	copied, err := service.Spreadsheets.Sheets.CopyTo(spreadsheetID, sheetID, copyReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to copy sheet: %w", err)
+	}
+	// Only the source ID is in the CopyTo URL, so the transport cannot see the new destination sheet
+	helpers.InvalidateSheetCache(destinationID)
+
+	title := copied.Title
+	if copySheetToName != "" && copySheetToName != title {
+		req := &sheets.Request{
+			UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+				Properties: &sheets.SheetProperties{
+					SheetId: copied.SheetId,
+					Title:   copySheetToName,
+				},
+				Fields: "title",
+			},
+		}
+
+		batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+			Requests: []*sheets.Request{req},
+		}
+
+		_, err = service.Spreadsheets.BatchUpdate(destinationID, batchReq).Do()
+		if err != nil {
+			return fmt.Errorf("sheet copied as '%s' but unable to rename it: %w", title, err)
+		}
+		title = copySheetToName
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":         "success",
+		"spreadsheet_id": destinationID,
+		"sheet_id":       copied.SheetId,
+		"sheet_name":     title,
+	})
+}
+
+var (
+	moveSheetBefore string
+	moveSheetAfter  string
+)
+
+var moveSheetCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "move-sheet <spreadsheet-id> <sheet-name> [index]",
+		Short: "Move a sheet to a new tab position",
+		Args:  cobra.RangeArgs(2, 3),
+		RunE:  runMoveSheet,
+	}
+	cmd.Flags().StringVar(&moveSheetBefore, "before", "", "Place the sheet before this sheet")
+	cmd.Flags().StringVar(&moveSheetAfter, "after", "", "Place the sheet after this sheet")
+	cmd.MarkFlagsMutuallyExclusive("before", "after")
+	return cmd
+}()
+
+func runMoveSheet(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	hasIndex := len(args) > 2
+	hasAnchor := moveSheetBefore != "" || moveSheetAfter != ""
+	if hasIndex == hasAnchor {
+		return fmt.Errorf("provide either an index or one of --before/--after")
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	spreadsheet, err := helpers.GetSpreadsheetSheets(service, spreadsheetID)
+	if err != nil {
+		return fmt.Errorf("unable to get spreadsheet: %w", err)
+	}
+
+	sheet, err := helpers.FindSheet(spreadsheet, sheetName)
+	if err != nil {
+		return err
+	}
+
+	// The API interprets the index against the order before the move,
+	// so a sheet moving right has to target one slot past its final position
+	var index int64
+	if hasIndex {
+		finalIndex, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil || finalIndex < 0 {
+			return fmt.Errorf("invalid index: %s", args[2])
+		}
+		index = finalIndex
+		if sheet.Index < finalIndex {
+			index++
+		}
+	} else {
+		anchorName := moveSheetBefore
+		if anchorName == "" {
+			anchorName = moveSheetAfter
+		}
+		anchor, err := helpers.FindSheet(spreadsheet, anchorName)
+		if err != nil {
+			return err
+		}
+		index = anchor.Index
+		if moveSheetAfter != "" {
+			index++
+		}
+	}
+
+	req := &sheets.Request{
+		UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+			Properties: &sheets.SheetProperties{
+				SheetId:         sheet.SheetId,
+				Index:           index,
+				ForceSendFields: []string{"Index"},
+			},
+			Fields: "index",
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to move sheet: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":     "success",
+		"sheet_name": sheetName,
+		"old_index":  sheet.Index,
+	})
+}
+
+var hideSheetCmd = &cobra.Command{
+	Use:   "hide-sheet <spreadsheet-id> <sheet-name>",
+	Short: "Hide a sheet",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setSheetHidden(args[0], args[1], true)
+	},
+}
+
+var showSheetCmd = &cobra.Command{
+	Use:   "show-sheet <spreadsheet-id> <sheet-name>",
+	Short: "Unhide a sheet",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setSheetHidden(args[0], args[1], false)
+	},
+}
+
+func setSheetHidden(spreadsheetID, sheetName string, hidden bool) error {
+	ctx := context.Background()
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.Request{
+		UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+			Properties: &sheets.SheetProperties{
+				SheetId:         sheetID,
+				Hidden:          hidden,
+				ForceSendFields: []string{"Hidden"},
+			},
+			Fields: "hidden",
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to update sheet visibility: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":     "success",
+		"sheet_name": sheetName,
+		"hidden":     hidden,
+	})
+}
+
+var (
+	resizeGridRows int
+	resizeGridCols int
+)
+
+var resizeGridCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resize-grid <spreadsheet-id> <sheet-name>",
+		Short: "Set the row and/or column count of a sheet",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runResizeGrid,
+	}
+	cmd.Flags().IntVar(&resizeGridRows, "rows", 0, "New row count")
+	cmd.Flags().IntVar(&resizeGridCols, "cols", 0, "New column count")
+	return cmd
+}()
+
+func runResizeGrid(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	if resizeGridRows <= 0 && resizeGridCols <= 0 {
+		return fmt.Errorf("at least one of --rows or --cols must be a positive number")
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	gridProps := &sheets.GridProperties{}
+	var fields []string
+	if resizeGridRows > 0 {
+		gridProps.RowCount = int64(resizeGridRows)
+		fields = append(fields, "gridProperties.rowCount")
+	}
+	if resizeGridCols > 0 {
+		gridProps.ColumnCount = int64(resizeGridCols)
+		fields = append(fields, "gridProperties.columnCount")
+	}
+
+	req := &sheets.Request{
+		UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+			Properties: &sheets.SheetProperties{
+				SheetId:        sheetID,
+				GridProperties: gridProps,
+			},
+			Fields: strings.Join(fields, ","),
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to resize grid: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":     "success",
+		"sheet_name": sheetName,
+		"rows":       gridProps.RowCount,
+		"cols":       gridProps.ColumnCount,
+	})
+}
+
 var renameSheetCmd = &cobra.Command{
 	Use:   "rename-sheet <spreadsheet-id> <old-name> <new-name>",
 	Short: "Rename a sheet",
@@ -100,12 +518,18 @@ func runRenameSheet(cmd *cobra.Command, args []string) error {
 	})
 }
 
-var listSheetsCmd = &cobra.Command{
-	Use:   "list-sheets <spreadsheet-id>",
-	Short: "List all sheets in the spreadsheet",
-	Args:  cobra.ExactArgs(1),
-	RunE:  runListSheets,
-}
+var listSheetsDetailed bool
+
+var listSheetsCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list-sheets <spreadsheet-id>",
+		Short: "List all sheets in the spreadsheet",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runListSheets,
+	}
+	cmd.Flags().BoolVar(&listSheetsDetailed, "detailed", false, "Include grid size, frozen rows/columns, tab color, and sheet type")
+	return cmd
+}()
 
 func runListSheets(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
@@ -116,18 +540,24 @@ func runListSheets(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	spreadsheet, err := service.Spreadsheets.Get(spreadsheetID).Do()
+	spreadsheet, err := helpers.GetSpreadsheetSheets(service, spreadsheetID)
 	if err != nil {
 		return fmt.Errorf("unable to get spreadsheet: %w", err)
 	}
 
 	var sheetsList []map[string]interface{}
 	for _, sheet := range spreadsheet.Sheets {
-		sheetsList = append(sheetsList, map[string]interface{}{
-			"sheet_id": sheet.Properties.SheetId,
-			"title":    sheet.Properties.Title,
-			"index":    sheet.Properties.Index,
-		})
+		props := sheet.Properties
+		entry := map[string]interface{}{
+			"sheet_id": props.SheetId,
+			"title":    props.Title,
+			"index":    props.Index,
+			"hidden":   props.Hidden,
+		}
+		if listSheetsDetailed {
+			addSheetDetails(entry, props)
+		}
+		sheetsList = append(sheetsList, entry)
 	}
 
 	return helpers.PrintJSON(map[string]interface{}{
@@ -136,19 +566,52 @@ func runListSheets(cmd *cobra.Command, args []string) error {
 	})
 }
 
-var addNoteCmd = &cobra.Command{
-	Use:   "add-note <spreadsheet-id> <sheet-name> <cell> <note>",
-	Short: "Add a note to a cell",
-	Args:  cobra.ExactArgs(4),
-	RunE:  runAddNote,
+func addSheetDetails(entry map[string]interface{}, props *sheets.SheetProperties) {
+	entry["sheet_type"] = props.SheetType
+
+	if grid := props.GridProperties; grid != nil {
+		entry["row_count"] = grid.RowCount
+		entry["column_count"] = grid.ColumnCount
+		entry["frozen_rows"] = grid.FrozenRowCount
+		entry["frozen_columns"] = grid.FrozenColumnCount
+	}
+
+	tabColor := props.TabColor
+	if props.TabColorStyle != nil && props.TabColorStyle.RgbColor != nil {
+		tabColor = props.TabColorStyle.RgbColor
+	}
+	if tabColor != nil {
+		entry["tab_color"] = helpers.ColorToHex(tabColor)
+	}
 }
 
-func runAddNote(cmd *cobra.Command, args []string) error {
+var (
+	freezeRows int
+	freezeCols int
+)
+
+var freezeCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "freeze <spreadsheet-id> <sheet-name>",
+		Short: "Freeze header rows and/or columns (0 unfreezes)",
+		Args:  cobra.ExactArgs(2),
+		RunE:  runFreeze,
+	}
+	cmd.Flags().IntVar(&freezeRows, "rows", 0, "Number of frozen rows")
+	cmd.Flags().IntVar(&freezeCols, "cols", 0, "Number of frozen columns")
+	return cmd
+}()
+
+func runFreeze(cmd *cobra.Command, args []string) error {
 	ctx := context.Background()
 	spreadsheetID := args[0]
 	sheetName := args[1]
-	cell := args[2]
-	note := args[3]
+
+	rowsSet := cmd.Flags().Changed("rows")
+	colsSet := cmd.Flags().Changed("cols")
+	if !rowsSet && !colsSet {
+		return fmt.Errorf("at least one of --rows or --cols is required")
+	}
 
 	service, err := auth.GetSheetsService(ctx)
 	if err != nil {
@@ -160,30 +623,15 @@ func runAddNote(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	col, row, err := helpers.A1ToGrid(cell)
-	if err != nil {
-		return err
+	var rows, cols *int
+	if rowsSet {
+		rows = &freezeRows
 	}
-
-	req := &sheets.Request{
-		UpdateCells: &sheets.UpdateCellsRequest{
-			Range: &sheets.GridRange{
-				SheetId:          sheetID,
-				StartRowIndex:    int64(row),
-				EndRowIndex:      int64(row + 1),
-				StartColumnIndex: int64(col),
-				EndColumnIndex:   int64(col + 1),
-			},
-			Rows: []*sheets.RowData{
-				{
-					Values: []*sheets.CellData{
-						{Note: note},
-					},
-				},
-			},
-			Fields: "note",
-		},
+	if colsSet {
+		cols = &freezeCols
 	}
+	req := freezeRequest(sheetID, rows, cols)
+	gridProps := req.UpdateSheetProperties.Properties.GridProperties
 
 	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
 		Requests: []*sheets.Request{req},
@@ -191,12 +639,39 @@ func runAddNote(cmd *cobra.Command, args []string) error {
 
 	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
 	if err != nil {
-		return fmt.Errorf("unable to add note: %w", err)
+		return fmt.Errorf("unable to freeze panes: %w", err)
 	}
 
 	return helpers.PrintJSON(map[string]interface{}{
-		"status":      "success",
-		"cell":        cell,
-		"note_length": len(note),
+		"status":         "success",
+		"sheet_name":     sheetName,
+		"frozen_rows":    gridProps.FrozenRowCount,
+		"frozen_columns": gridProps.FrozenColumnCount,
 	})
 }
+
+// freezeRequest sets the frozen row and/or column count of a sheet; nil leaves it unchanged
+func freezeRequest(sheetID int64, rows, cols *int) *sheets.Request {
+	gridProps := &sheets.GridProperties{}
+	var fields []string
+	if rows != nil {
+		gridProps.FrozenRowCount = int64(*rows)
+		gridProps.ForceSendFields = append(gridProps.ForceSendFields, "FrozenRowCount")
+		fields = append(fields, "gridProperties.frozenRowCount")
+	}
+	if cols != nil {
+		gridProps.FrozenColumnCount = int64(*cols)
+		gridProps.ForceSendFields = append(gridProps.ForceSendFields, "FrozenColumnCount")
+		fields = append(fields, "gridProperties.frozenColumnCount")
+	}
+
+	return &sheets.Request{
+		UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+			Properties: &sheets.SheetProperties{
+				SheetId:        sheetID,
+				GridProperties: gridProps,
+			},
+			Fields: strings.Join(fields, ","),
+		},
+	}
+}
@@ -0,0 +1,327 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+var spreadsheetInfoCmd = &cobra.Command{
+	Use:   "spreadsheet-info <spreadsheet-id>",
+	Short: "Print spreadsheet properties: title, locale, time zone, recalculation, theme, named ranges",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSpreadsheetInfo,
+}
+
+func runSpreadsheetInfo(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetID).
+		Fields("spreadsheetId,spreadsheetUrl,properties,sheets.properties(sheetId,title),namedRanges").Do()
+	if err != nil {
+		return fmt.Errorf("unable to get spreadsheet: %w", err)
+	}
+
+	props := spreadsheet.Properties
+	info := map[string]interface{}{
+		"status":      "success",
+		"id":          spreadsheet.SpreadsheetId,
+		"url":         spreadsheet.SpreadsheetUrl,
+		"title":       props.Title,
+		"locale":      props.Locale,
+		"time_zone":   props.TimeZone,
+		"auto_recalc": props.AutoRecalc,
+		"sheet_count": len(spreadsheet.Sheets),
+	}
+
+	if calc := props.IterativeCalculationSettings; calc != nil {
+		info["iterative_calculation"] = map[string]interface{}{
+			"max_iterations":        calc.MaxIterations,
+			"convergence_threshold": calc.ConvergenceThreshold,
+		}
+	}
+
+	if theme := props.SpreadsheetTheme; theme != nil {
+		colors := map[string]string{}
+		for _, pair := range theme.ThemeColors {
+			if pair.Color != nil && pair.Color.RgbColor != nil {
+				colors[pair.ColorType] = helpers.ColorToHex(pair.Color.RgbColor)
+			}
+		}
+		info["theme"] = map[string]interface{}{
+			"font_family": theme.PrimaryFontFamily,
+			"colors":      colors,
+		}
+	}
+
+	titles := map[int64]string{}
+	for _, sheet := range spreadsheet.Sheets {
+		titles[sheet.Properties.SheetId] = sheet.Properties.Title
+	}
+
+	namedRanges := make([]map[string]string, 0, len(spreadsheet.NamedRanges))
+	for _, named := range spreadsheet.NamedRanges {
+		namedRanges = append(namedRanges, map[string]string{
+			"id":    named.NamedRangeId,
+			"name":  named.Name,
+			"range": namedRangeA1(named.Range, titles),
+		})
+	}
+	info["named_ranges"] = namedRanges
+
+	return helpers.PrintJSON(info)
+}
+
+// namedRangeA1 renders a named range as a sheet-qualified A1 range
+func namedRangeA1(gridRange *sheets.GridRange, titles map[int64]string) string {
+	if gridRange == nil {
+		return ""
+	}
+	return helpers.SheetRange(titles[gridRange.SheetId], helpers.GridRangeToA1(gridRange))
+}
+
+var (
+	setPropertiesTitle                string
+	setPropertiesLocale               string
+	setPropertiesTimeZone             string
+	setPropertiesRecalc               string
+	setPropertiesIterativeCalc        bool
+	setPropertiesMaxIterations        int64
+	setPropertiesConvergenceThreshold float64
+)
+
+var setPropertiesCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-properties <spreadsheet-id>",
+		Short: "Change the title, locale, time zone or calculation settings of a spreadsheet",
+		Long: `Change the title, locale, time zone or calculation settings of a spreadsheet.
+
+Only the given flags are changed. The locale drives date and number formats,
+so set it right after creating a spreadsheet (new ones default to en_US).
+--iterative-calc=false turns iterative calculation off.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runSetProperties,
+	}
+	cmd.Flags().StringVar(&setPropertiesTitle, "title", "", "Spreadsheet title")
+	cmd.Flags().StringVar(&setPropertiesLocale, "locale", "", "Locale (e.g. fr_FR, en_GB)")
+	cmd.Flags().StringVar(&setPropertiesTimeZone, "timezone", "", "Time zone (e.g. Europe/Paris)")
+	cmd.Flags().StringVar(&setPropertiesRecalc, "recalc", "", "Recalculation of volatile functions: ON_CHANGE, MINUTE or HOUR")
+	cmd.Flags().BoolVar(&setPropertiesIterativeCalc, "iterative-calc", false, "Enable iterative calculation of circular references")
+	cmd.Flags().Int64Var(&setPropertiesMaxIterations, "max-iterations", 50, "Iterations per calculation with --iterative-calc")
+	cmd.Flags().Float64Var(&setPropertiesConvergenceThreshold, "convergence-threshold", 0.05, "Threshold below which iterations stop with --iterative-calc")
+	return cmd
+}()
+
+func runSetProperties(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	flags := cmd.Flags()
+
+	props := &sheets.SpreadsheetProperties{}
+	var fields []string
+
+	if flags.Changed("title") {
+		props.Title = setPropertiesTitle
+		fields = append(fields, "title")
+	}
+	if flags.Changed("locale") {
+		props.Locale = setPropertiesLocale
+		fields = append(fields, "locale")
+	}
+	if flags.Changed("timezone") {
+		props.TimeZone = setPropertiesTimeZone
+		fields = append(fields, "timeZone")
+	}
+	if flags.Changed("recalc") {
+		switch setPropertiesRecalc {
+		case "ON_CHANGE", "MINUTE", "HOUR":
+		default:
+			return fmt.Errorf("invalid --recalc '%s': expected ON_CHANGE, MINUTE or HOUR", setPropertiesRecalc)
+		}
+		props.AutoRecalc = setPropertiesRecalc
+		fields = append(fields, "autoRecalc")
+	}
+
+	iterativeTuned := flags.Changed("max-iterations") || flags.Changed("convergence-threshold")
+	if iterativeTuned && !flags.Changed("iterative-calc") {
+		return fmt.Errorf("--max-iterations and --convergence-threshold require --iterative-calc")
+	}
+	if flags.Changed("iterative-calc") {
+		// Clearing the settings is what turns iterative calculation off
+		if setPropertiesIterativeCalc {
+			props.IterativeCalculationSettings = &sheets.IterativeCalculationSettings{
+				MaxIterations:        setPropertiesMaxIterations,
+				ConvergenceThreshold: setPropertiesConvergenceThreshold,
+			}
+		}
+		fields = append(fields, "iterativeCalculationSettings")
+	}
+
+	if len(fields) == 0 {
+		return fmt.Errorf("nothing to change: pass at least one of --title, --locale, --timezone, --recalc or --iterative-calc")
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.Request{
+		UpdateSpreadsheetProperties: &sheets.UpdateSpreadsheetPropertiesRequest{
+			Properties: props,
+			Fields:     strings.Join(fields, ","),
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to update spreadsheet properties: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":  "success",
+		"updated": fields,
+	})
+}
+
+// themeColorTypes are the color slots of a spreadsheet theme
+var themeColorTypes = []string{"TEXT", "BACKGROUND", "ACCENT1", "ACCENT2", "ACCENT3", "ACCENT4", "ACCENT5", "ACCENT6", "LINK"}
+
+type themeSpec struct {
+	FontFamily string            `json:"font_family"`
+	Colors     map[string]string `json:"colors"`
+}
+
+var setThemeCmd = &cobra.Command{
+	Use:   "set-theme <spreadsheet-id> <theme.json|->",
+	Short: "Apply a theme (font and color palette) from a JSON file",
+	Long: `Apply a theme (primary font and color palette) from a JSON file ("-" reads stdin).
+
+Example theme:
+  {
+    "font_family": "Roboto",
+    "colors": {"TEXT": "#212121", "BACKGROUND": "#ffffff", "ACCENT1": "#0b5394",
+               "ACCENT2": "#e69138", "LINK": "#1155cc"}
+  }
+
+Color keys are TEXT, BACKGROUND, ACCENT1 to ACCENT6 and LINK; colors left out
+keep their current value. The "theme" object printed by spreadsheet-info has
+the same shape, so a theme can be copied from one spreadsheet to another.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runSetTheme,
+}
+
+func runSetTheme(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	themePath := args[1]
+
+	var data []byte
+	var err error
+	if themePath == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(themePath)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read theme: %w", err)
+	}
+
+	var spec themeSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return fmt.Errorf("invalid theme: %w", err)
+	}
+
+	colors := map[string]*sheets.Color{}
+	for colorType, hex := range spec.Colors {
+		colorType = strings.ToUpper(colorType)
+		if !slices.Contains(themeColorTypes, colorType) {
+			return fmt.Errorf("invalid theme color '%s': expected one of %s", colorType, strings.Join(themeColorTypes, ", "))
+		}
+		color := helpers.ParseColor(hex)
+		if color == nil {
+			return fmt.Errorf("invalid color for %s: %s", colorType, hex)
+		}
+		colors[colorType] = color
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The API only accepts a complete theme, so start from the current one
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetID).Fields("properties.spreadsheetTheme").Do()
+	if err != nil {
+		return fmt.Errorf("unable to get spreadsheet: %w", err)
+	}
+
+	var theme *sheets.SpreadsheetTheme
+	if spreadsheet.Properties != nil {
+		theme = spreadsheet.Properties.SpreadsheetTheme
+	}
+	if theme == nil {
+		theme = &sheets.SpreadsheetTheme{}
+	}
+	if spec.FontFamily != "" {
+		theme.PrimaryFontFamily = spec.FontFamily
+	}
+
+	for colorType, color := range colors {
+		found := false
+		for _, pair := range theme.ThemeColors {
+			if pair.ColorType == colorType {
+				pair.Color = &sheets.ColorStyle{RgbColor: color}
+				found = true
+			}
+		}
+		if !found {
+			theme.ThemeColors = append(theme.ThemeColors, &sheets.ThemeColorPair{
+				ColorType: colorType,
+				Color:     &sheets.ColorStyle{RgbColor: color},
+			})
+		}
+	}
+
+	req := &sheets.Request{
+		UpdateSpreadsheetProperties: &sheets.UpdateSpreadsheetPropertiesRequest{
+			Properties: &sheets.SpreadsheetProperties{SpreadsheetTheme: theme},
+			Fields:     "spreadsheetTheme",
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to set theme: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":      "success",
+		"font_family": theme.PrimaryFontFamily,
+		"colors":      len(colors),
+	})
+}
@@ -13,11 +13,19 @@ import (
 )
 
 var (
-	styleCellsBgColor   string
-	styleCellsFontColor string
-	styleCellsFontSize  int
-	styleCellsBold      bool
-	styleCellsItalic    bool
+	styleCellsBgColor    string
+	styleCellsFontColor  string
+	styleCellsFontSize   int
+	styleCellsBold       bool
+	styleCellsItalic     bool
+	styleCellsUnderline  bool
+	styleCellsStrike     bool
+	styleCellsFontFamily string
+	styleCellsHAlign     string
+	styleCellsVAlign     string
+	styleCellsWrap       string
+	styleCellsRotation   int
+	styleCellsVertical   bool
 )
 
 var styleCellsCmd = func() *cobra.Command {
@@ -32,6 +40,15 @@ var styleCellsCmd = func() *cobra.Command {
 	cmd.Flags().IntVar(&styleCellsFontSize, "font-size", 0, "Font size")
 	cmd.Flags().BoolVar(&styleCellsBold, "bold", false, "Bold text")
 	cmd.Flags().BoolVar(&styleCellsItalic, "italic", false, "Italic text")
+	cmd.Flags().BoolVar(&styleCellsUnderline, "underline", false, "Underlined text")
+	cmd.Flags().BoolVar(&styleCellsStrike, "strikethrough", false, "Strikethrough text")
+	cmd.Flags().StringVar(&styleCellsFontFamily, "font-family", "", "Font family (e.g. Roboto)")
+	cmd.Flags().StringVar(&styleCellsHAlign, "h-align", "", "Horizontal alignment (LEFT, CENTER, RIGHT)")
+	cmd.Flags().StringVar(&styleCellsVAlign, "v-align", "", "Vertical alignment (TOP, MIDDLE, BOTTOM)")
+	cmd.Flags().StringVar(&styleCellsWrap, "wrap", "", "Wrap strategy (WRAP, CLIP, OVERFLOW)")
+	cmd.Flags().IntVar(&styleCellsRotation, "text-rotation", 0, "Text rotation angle in degrees (-90 to 90)")
+	cmd.Flags().BoolVar(&styleCellsVertical, "vertical", false, "Stack text vertically")
+	cmd.MarkFlagsMutuallyExclusive("text-rotation", "vertical")
 	return cmd
 }()
 
@@ -51,29 +68,36 @@ func runStyleCells(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	startCol, startRow, endCol, endRow, err := helpers.ParseRange(rangeA1)
+	gridRange, err := boundedGridRange(sheetID, rangeA1)
 	if err != nil {
 		return err
 	}
 
-	cellFormat, fields := buildCellFormat()
+	style := cellStyle{
+		BgColor:       styleCellsBgColor,
+		FontColor:     styleCellsFontColor,
+		FontSize:      styleCellsFontSize,
+		Bold:          styleCellsBold,
+		Italic:        styleCellsItalic,
+		Underline:     styleCellsUnderline,
+		Strikethrough: styleCellsStrike,
+		FontFamily:    styleCellsFontFamily,
+		HAlign:        styleCellsHAlign,
+		VAlign:        styleCellsVAlign,
+		Wrap:          styleCellsWrap,
+		Vertical:      styleCellsVertical,
+	}
+	if cmd.Flags().Changed("text-rotation") {
+		style.TextRotation = &styleCellsRotation
+	}
 
-	req := &sheets.Request{
-		RepeatCell: &sheets.RepeatCellRequest{
-			Range: &sheets.GridRange{
-				SheetId:          sheetID,
-				StartRowIndex:    int64(startRow),
-				EndRowIndex:      int64(endRow + 1),
-				StartColumnIndex: int64(startCol),
-				EndColumnIndex:   int64(endCol + 1),
-			},
-			Cell: &sheets.CellData{
-				UserEnteredFormat: cellFormat,
-			},
-			Fields: strings.Join(fields, ","),
-		},
+	cellFormat, fields := buildCellFormat(style)
+	if len(fields) == 0 {
+		return fmt.Errorf("no style options provided")
 	}
 
+	req := cellFormatRequest(gridRange, cellFormat, fields)
+
 	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
 		Requests: []*sheets.Request{req},
 	}
@@ -88,32 +112,93 @@ func runStyleCells(cmd *cobra.Command, args []string) error {
 	})
 }
 
-func buildCellFormat() (*sheets.CellFormat, []string) {
+// cellStyle holds the style-cells options; batch plans use the same keys
+type cellStyle struct {
+	BgColor       string `json:"bg_color"`
+	FontColor     string `json:"font_color"`
+	FontSize      int    `json:"font_size"`
+	Bold          bool   `json:"bold"`
+	Italic        bool   `json:"italic"`
+	Underline     bool   `json:"underline"`
+	Strikethrough bool   `json:"strikethrough"`
+	FontFamily    string `json:"font_family"`
+	HAlign        string `json:"h_align"`
+	VAlign        string `json:"v_align"`
+	Wrap          string `json:"wrap"`
+	TextRotation  *int   `json:"text_rotation"`
+	Vertical      bool   `json:"vertical"`
+}
+
+func buildCellFormat(style cellStyle) (*sheets.CellFormat, []string) {
 	cellFormat := &sheets.CellFormat{}
 	var fields []string
 
-	if styleCellsBgColor != "" {
-		cellFormat.BackgroundColor = helpers.ParseColor(styleCellsBgColor)
+	if style.BgColor != "" {
+		cellFormat.BackgroundColor = helpers.ParseColor(style.BgColor)
 		fields = append(fields, "userEnteredFormat.backgroundColor")
 	}
 
-	if styleCellsFontColor != "" || styleCellsFontSize > 0 || styleCellsBold || styleCellsItalic {
+	if style.FontColor != "" || style.FontSize > 0 || style.Bold || style.Italic ||
+		style.Underline || style.Strikethrough || style.FontFamily != "" {
 		textFormat := &sheets.TextFormat{}
-		if styleCellsFontColor != "" {
-			textFormat.ForegroundColor = helpers.ParseColor(styleCellsFontColor)
+		if style.FontColor != "" {
+			textFormat.ForegroundColor = helpers.ParseColor(style.FontColor)
 		}
-		if styleCellsFontSize > 0 {
-			textFormat.FontSize = int64(styleCellsFontSize)
+		if style.FontSize > 0 {
+			textFormat.FontSize = int64(style.FontSize)
 		}
-		if styleCellsBold {
+		if style.Bold {
 			textFormat.Bold = true
 		}
-		if styleCellsItalic {
+		if style.Italic {
 			textFormat.Italic = true
 		}
+		if style.Underline {
+			textFormat.Underline = true
+		}
+		if style.Strikethrough {
+			textFormat.Strikethrough = true
+		}
+		if style.FontFamily != "" {
+			textFormat.FontFamily = style.FontFamily
+		}
 		cellFormat.TextFormat = textFormat
 		fields = append(fields, "userEnteredFormat.textFormat")
 	}
 
+	if style.HAlign != "" {
+		cellFormat.HorizontalAlignment = strings.ToUpper(style.HAlign)
+		fields = append(fields, "userEnteredFormat.horizontalAlignment")
+	}
+
+	if style.VAlign != "" {
+		cellFormat.VerticalAlignment = strings.ToUpper(style.VAlign)
+		fields = append(fields, "userEnteredFormat.verticalAlignment")
+	}
+
+	if style.Wrap != "" {
+		cellFormat.WrapStrategy = wrapStrategy(style.Wrap)
+		fields = append(fields, "userEnteredFormat.wrapStrategy")
+	}
+
+	if style.TextRotation != nil || style.Vertical {
+		rotation := &sheets.TextRotation{Vertical: style.Vertical}
+		if !style.Vertical {
+			rotation.Angle = int64(*style.TextRotation)
+			rotation.ForceSendFields = []string{"Angle"}
+		}
+		cellFormat.TextRotation = rotation
+		fields = append(fields, "userEnteredFormat.textRotation")
+	}
+
 	return cellFormat, fields
 }
+
+// wrapStrategy maps the short --wrap values to API wrap strategies
+func wrapStrategy(value string) string {
+	value = strings.ToUpper(value)
+	if value == "OVERFLOW" {
+		return WrapStrategyOverflow
+	}
+	return value
+}
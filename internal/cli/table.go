@@ -0,0 +1,402 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+var (
+	makeTableBoldHeader bool
+	makeTableFreeze     bool
+	makeTableAutoResize bool
+	makeTableFilter     bool
+	makeTableBanding    bool
+	makeTablePalette    string
+)
+
+var makeTableCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "make-table <spreadsheet-id> <sheet-name> [range]",
+		Short: "Turn a data range into a table (bold header, freeze, resize, filter, banding)",
+		Long: `Turn a data range into a table in a single batch update:
+bold the header row, freeze it, auto-resize the columns, add a basic filter
+and apply banding. Each step can be turned off, e.g. --banding=false.
+
+Without [range], the populated extent of the sheet is used.`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: runMakeTable,
+	}
+	cmd.Flags().BoolVar(&makeTableBoldHeader, "bold-header", true, "Bold the header row")
+	cmd.Flags().BoolVar(&makeTableFreeze, "freeze", true, "Freeze rows down to the header")
+	cmd.Flags().BoolVar(&makeTableAutoResize, "auto-resize", true, "Auto-resize the table columns")
+	cmd.Flags().BoolVar(&makeTableFilter, "filter", true, "Add a basic filter over the table")
+	cmd.Flags().BoolVar(&makeTableBanding, "banding", true, "Apply alternating row colors")
+	cmd.Flags().StringVar(&makeTablePalette, "palette", DefaultBandingPalette, "Banding palette ("+strings.Join(bandingPaletteNames(), ", ")+")")
+	return cmd
+}()
+
+func runMakeTable(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	palette, ok := bandingPalettes[strings.ToLower(makeTablePalette)]
+	if !ok {
+		return fmt.Errorf("unknown palette '%s' (available: %s)", makeTablePalette, strings.Join(bandingPaletteNames(), ", "))
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheet, err := helpers.GetSheetProperties(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	var rangeA1 string
+	if len(args) == 3 {
+		rangeA1 = args[2]
+	} else {
+		rangeA1, err = detectDataExtent(service, spreadsheetID, sheet.Title)
+		if err != nil {
+			return err
+		}
+	}
+
+	table, err := helpers.ParseGridRange(sheet.SheetId, rangeA1)
+	if err != nil {
+		return err
+	}
+	if table.EndRowIndex == 0 || table.EndColumnIndex == 0 {
+		return fmt.Errorf("table range '%s' must be bounded (e.g. A1:F200)", rangeA1)
+	}
+
+	header := &sheets.GridRange{
+		SheetId:          table.SheetId,
+		StartRowIndex:    table.StartRowIndex,
+		EndRowIndex:      table.StartRowIndex + 1,
+		StartColumnIndex: table.StartColumnIndex,
+		EndColumnIndex:   table.EndColumnIndex,
+	}
+
+	var requests []*sheets.Request
+	var steps []string
+
+	if makeTableBoldHeader {
+		requests = append(requests, &sheets.Request{
+			RepeatCell: &sheets.RepeatCellRequest{
+				Range: header,
+				Cell: &sheets.CellData{
+					UserEnteredFormat: &sheets.CellFormat{
+						TextFormat: &sheets.TextFormat{Bold: true},
+					},
+				},
+				Fields: "userEnteredFormat.textFormat.bold",
+			},
+		})
+		steps = append(steps, "bold_header")
+	}
+
+	if makeTableFreeze {
+		requests = append(requests, &sheets.Request{
+			UpdateSheetProperties: &sheets.UpdateSheetPropertiesRequest{
+				Properties: &sheets.SheetProperties{
+					SheetId: table.SheetId,
+					GridProperties: &sheets.GridProperties{
+						FrozenRowCount: table.StartRowIndex + 1,
+					},
+				},
+				Fields: "gridProperties.frozenRowCount",
+			},
+		})
+		steps = append(steps, "freeze")
+	}
+
+	if makeTableAutoResize {
+		requests = append(requests, &sheets.Request{
+			AutoResizeDimensions: &sheets.AutoResizeDimensionsRequest{
+				Dimensions: &sheets.DimensionRange{
+					SheetId:    table.SheetId,
+					Dimension:  MajorDimensionColumns,
+					StartIndex: table.StartColumnIndex,
+					EndIndex:   table.EndColumnIndex,
+				},
+			},
+		})
+		steps = append(steps, "auto_resize")
+	}
+
+	if makeTableFilter {
+		requests = append(requests, &sheets.Request{
+			SetBasicFilter: &sheets.SetBasicFilterRequest{
+				Filter: &sheets.BasicFilter{Range: table},
+			},
+		})
+		steps = append(steps, "filter")
+	}
+
+	if makeTableBanding {
+		requests = append(requests, &sheets.Request{
+			AddBanding: &sheets.AddBandingRequest{
+				BandedRange: &sheets.BandedRange{
+					Range: table,
+					RowProperties: &sheets.BandingProperties{
+						HeaderColorStyle:     colorStyle(palette.header),
+						FirstBandColorStyle:  colorStyle(palette.first),
+						SecondBandColorStyle: colorStyle(palette.second),
+					},
+				},
+			},
+		})
+		steps = append(steps, "banding")
+	}
+
+	if len(requests) == 0 {
+		return fmt.Errorf("all steps are disabled")
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: requests,
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to make table: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status": "success",
+		"range":  rangeA1,
+		"steps":  steps,
+	})
+}
+
+// detectDataExtent returns the A1 range from A1 to the last populated row and column of a sheet
+func detectDataExtent(service *sheets.Service, spreadsheetID, sheetTitle string) (string, error) {
+	resp, err := service.Spreadsheets.Values.Get(spreadsheetID, helpers.SheetRange(sheetTitle, "")).Do()
+	if err != nil {
+		return "", fmt.Errorf("unable to detect data extent: %w", err)
+	}
+
+	cols := 0
+	for _, row := range resp.Values {
+		cols = max(cols, len(row))
+	}
+	if len(resp.Values) == 0 || cols == 0 {
+		return "", fmt.Errorf("sheet '%s' has no data", sheetTitle)
+	}
+
+	return DefaultStartCell + ":" + helpers.GridToA1(cols-1, len(resp.Values)-1), nil
+}
+
+var (
+	addTotalsRowFunction string
+	addTotalsRowColumns  []string
+	addTotalsRowLabel    string
+)
+
+var addTotalsRowCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add-totals-row <spreadsheet-id> <sheet-name> [range]",
+		Short: "Append a totals row with SUM/AVERAGE/COUNT formulas under a table",
+		Long: `Append a totals row under a table (default: populated extent of the sheet).
+
+Every numeric column gets a --function formula over its data rows (the first
+row is the header). Override per column with --column "D=AVERAGE" or skip one
+with --column "E=NONE". The row is inserted under the table, so nothing below
+is overwritten, and is bolded with a top border.`,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: runAddTotalsRow,
+	}
+	cmd.Flags().StringVar(&addTotalsRowFunction, "function", "SUM", "Default function for numeric columns (SUM, AVERAGE, COUNT, MIN, MAX...)")
+	cmd.Flags().StringArrayVar(&addTotalsRowColumns, "column", nil, "COLUMN=FUNCTION override, NONE to skip (repeatable)")
+	cmd.Flags().StringVar(&addTotalsRowLabel, "label", "Total", "Label written in the first column when it is not numeric")
+	return cmd
+}()
+
+func runAddTotalsRow(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+
+	overrides := map[int]string{}
+	for _, spec := range addTotalsRowColumns {
+		column, function, ok := strings.Cut(spec, "=")
+		if !ok {
+			return fmt.Errorf("invalid --column '%s': expected COLUMN=FUNCTION", spec)
+		}
+		col, err := helpers.ColumnIndex(strings.TrimSpace(column))
+		if err != nil {
+			return err
+		}
+		overrides[col] = strings.ToUpper(strings.TrimSpace(function))
+	}
+
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheet, err := helpers.GetSheetProperties(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	var rangeA1 string
+	if len(args) == 3 {
+		rangeA1 = args[2]
+	} else {
+		rangeA1, err = detectDataExtent(service, spreadsheetID, sheet.Title)
+		if err != nil {
+			return err
+		}
+	}
+
+	table, err := helpers.ParseGridRange(sheet.SheetId, rangeA1)
+	if err != nil {
+		return err
+	}
+	if table.EndRowIndex == 0 || table.EndColumnIndex == 0 {
+		return fmt.Errorf("table range '%s' must be bounded (e.g. A1:F200)", rangeA1)
+	}
+	if table.EndRowIndex-table.StartRowIndex < 2 {
+		return fmt.Errorf("table range '%s' has no data rows below the header", rangeA1)
+	}
+
+	resp, err := service.Spreadsheets.Values.Get(spreadsheetID, helpers.SheetRange(sheet.Title, rangeA1)).
+		ValueRenderOption(ValueRenderUnformatted).Do()
+	if err != nil {
+		return fmt.Errorf("unable to read table: %w", err)
+	}
+
+	if rows := len(resp.Values); rows > 2 && rows == int(table.EndRowIndex-table.StartRowIndex) &&
+		len(resp.Values[rows-1]) > 0 && helpers.CellString(resp.Values[rows-1][0]) == addTotalsRowLabel {
+		return fmt.Errorf("table range '%s' already ends with a '%s' row", rangeA1, addTotalsRowLabel)
+	}
+
+	firstDataRow := table.StartRowIndex + 2
+	lastDataRow := table.EndRowIndex
+	width := int(table.EndColumnIndex - table.StartColumnIndex)
+
+	totals := make([]*sheets.CellData, width)
+	formulas := 0
+	for i := 0; i < width; i++ {
+		col := int(table.StartColumnIndex) + i
+		function, ok := overrides[col]
+		if !ok && columnKind(resp.Values, i) == "number" {
+			function = strings.ToUpper(addTotalsRowFunction)
+		}
+		if function == "" || function == "NONE" {
+			totals[i] = &sheets.CellData{}
+			continue
+		}
+		letters := helpers.ColumnToLetters(col)
+		formula := fmt.Sprintf("=%s(%s%d:%s%d)", function, letters, firstDataRow, letters, lastDataRow)
+		totals[i] = &sheets.CellData{UserEnteredValue: &sheets.ExtendedValue{FormulaValue: &formula}}
+		formulas++
+	}
+	if totals[0].UserEnteredValue == nil {
+		totals[0] = &sheets.CellData{UserEnteredValue: &sheets.ExtendedValue{StringValue: &addTotalsRowLabel}}
+	}
+	if formulas == 0 {
+		return fmt.Errorf("no numeric columns found in %s", rangeA1)
+	}
+
+	totalsRow := table.EndRowIndex
+	totalsA1 := helpers.GridToA1(int(table.StartColumnIndex), int(totalsRow)) + ":" +
+		helpers.GridToA1(int(table.EndColumnIndex-1), int(totalsRow))
+
+	rowRange := &sheets.GridRange{
+		SheetId:          sheet.SheetId,
+		StartRowIndex:    totalsRow,
+		EndRowIndex:      totalsRow + 1,
+		StartColumnIndex: table.StartColumnIndex,
+		EndColumnIndex:   table.EndColumnIndex,
+	}
+
+	// A fresh row keeps whatever sits under the table and works when the table ends the grid
+	requests := []*sheets.Request{
+		{
+			InsertDimension: &sheets.InsertDimensionRequest{
+				Range: &sheets.DimensionRange{
+					SheetId:    sheet.SheetId,
+					Dimension:  MajorDimensionRows,
+					StartIndex: totalsRow,
+					EndIndex:   totalsRow + 1,
+				},
+				InheritFromBefore: true,
+			},
+		},
+		{
+			UpdateCells: &sheets.UpdateCellsRequest{
+				Range:  rowRange,
+				Rows:   []*sheets.RowData{{Values: totals}},
+				Fields: "userEnteredValue",
+			},
+		},
+		{
+			RepeatCell: &sheets.RepeatCellRequest{
+				Range: rowRange,
+				Cell: &sheets.CellData{
+					UserEnteredFormat: &sheets.CellFormat{
+						TextFormat: &sheets.TextFormat{Bold: true},
+					},
+				},
+				Fields: "userEnteredFormat.textFormat.bold",
+			},
+		},
+		{
+			UpdateBorders: &sheets.UpdateBordersRequest{
+				Range: rowRange,
+				Top:   &sheets.Border{Style: "SOLID_MEDIUM"},
+			},
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: requests,
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to write totals row: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":   "success",
+		"range":    totalsA1,
+		"formulas": formulas,
+	})
+}
+
+// columnKind returns "number" or "bool" when every non-empty data cell (header excluded) of a column
+// has that type, "text" for mixed or text columns and "" for empty ones
+func columnKind(values [][]interface{}, col int) string {
+	kind := ""
+	for _, row := range values[min(1, len(values)):] {
+		if col >= len(row) || row[col] == "" {
+			continue
+		}
+		cellKind := "text"
+		switch row[col].(type) {
+		case float64:
+			cellKind = "number"
+		case bool:
+			cellKind = "bool"
+		}
+		if kind != "" && kind != cellKind {
+			return "text"
+		}
+		kind = cellKind
+	}
+	return kind
+}
@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/api/sheets/v4"
+
+	"spreadsheet-manager/internal/auth"
+	"spreadsheet-manager/internal/helpers"
+)
+
+var (
+	setValidationValues        []string
+	setValidationFromRange     string
+	setValidationCustomFormula string
+	setValidationStrict        bool
+	setValidationReject        bool
+	setValidationWarn          bool
+	setValidationInputMessage  string
+	setValidationShowDropdown  bool
+)
+
+var setValidationCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-validation <spreadsheet-id> <sheet-name> <range>",
+		Short: "Add dropdown or custom-formula data validation to a range",
+		Args:  cobra.ExactArgs(3),
+		RunE:  runSetValidation,
+	}
+	cmd.Flags().StringSliceVar(&setValidationValues, "values", nil, "Comma-separated list of allowed values")
+	cmd.Flags().StringVar(&setValidationFromRange, "from-range", "", "Range holding the allowed values (e.g. Lists!A1:A10)")
+	cmd.Flags().StringVar(&setValidationCustomFormula, "custom-formula", "", "Custom formula that must evaluate to TRUE (e.g. =ISNUMBER(A1))")
+	cmd.Flags().BoolVar(&setValidationStrict, "strict", false, "Reject input that does not match")
+	cmd.Flags().BoolVar(&setValidationReject, "reject", false, "Reject invalid input (same as --strict)")
+	cmd.Flags().BoolVar(&setValidationWarn, "warn", false, "Accept invalid input but show a warning (default)")
+	cmd.Flags().StringVar(&setValidationInputMessage, "input-message", "", "Help message shown when a cell is selected")
+	cmd.Flags().BoolVar(&setValidationShowDropdown, "show-dropdown", true, "Show a dropdown arrow in the cells")
+	cmd.MarkFlagsMutuallyExclusive("values", "from-range", "custom-formula")
+	cmd.MarkFlagsMutuallyExclusive("reject", "warn")
+	cmd.MarkFlagsMutuallyExclusive("strict", "warn")
+	return cmd
+}()
+
+func runSetValidation(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	rangeA1 := args[2]
+
+	condition, err := buildValidationCondition()
+	if err != nil {
+		return err
+	}
+
+	rule := &sheets.DataValidationRule{
+		Condition:    condition,
+		Strict:       setValidationStrict || setValidationReject,
+		ShowCustomUi: setValidationShowDropdown,
+		InputMessage: setValidationInputMessage,
+	}
+
+	return applyDataValidation(ctx, spreadsheetID, sheetName, rangeA1, rule)
+}
+
+func buildValidationCondition() (*sheets.BooleanCondition, error) {
+	switch {
+	case len(setValidationValues) > 0:
+		condition := &sheets.BooleanCondition{Type: "ONE_OF_LIST"}
+		for _, value := range setValidationValues {
+			condition.Values = append(condition.Values, &sheets.ConditionValue{UserEnteredValue: value})
+		}
+		return condition, nil
+	case setValidationFromRange != "":
+		source := setValidationFromRange
+		if !strings.HasPrefix(source, "=") {
+			source = "=" + source
+		}
+		return &sheets.BooleanCondition{
+			Type:   "ONE_OF_RANGE",
+			Values: []*sheets.ConditionValue{{UserEnteredValue: source}},
+		}, nil
+	case setValidationCustomFormula != "":
+		formula := setValidationCustomFormula
+		if !strings.HasPrefix(formula, "=") {
+			formula = "=" + formula
+		}
+		return &sheets.BooleanCondition{
+			Type:   "CUSTOM_FORMULA",
+			Values: []*sheets.ConditionValue{{UserEnteredValue: formula}},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("one of --values, --from-range or --custom-formula is required")
+}
+
+// applyDataValidation sets a validation rule on a range with SetDataValidationRequest
+func applyDataValidation(ctx context.Context, spreadsheetID, sheetName, rangeA1 string, rule *sheets.DataValidationRule) error {
+	service, err := auth.GetSheetsService(ctx)
+	if err != nil {
+		return err
+	}
+
+	sheetID, err := helpers.GetSheetID(service, spreadsheetID, sheetName)
+	if err != nil {
+		return err
+	}
+
+	gridRange, err := helpers.ParseGridRange(sheetID, rangeA1)
+	if err != nil {
+		return err
+	}
+
+	req := &sheets.Request{
+		SetDataValidation: &sheets.SetDataValidationRequest{
+			Range: gridRange,
+			Rule:  rule,
+		},
+	}
+
+	batchReq := &sheets.BatchUpdateSpreadsheetRequest{
+		Requests: []*sheets.Request{req},
+	}
+
+	_, err = service.Spreadsheets.BatchUpdate(spreadsheetID, batchReq).Do()
+	if err != nil {
+		return fmt.Errorf("unable to set data validation: %w", err)
+	}
+
+	return helpers.PrintJSON(map[string]interface{}{
+		"status":    "success",
+		"range":     rangeA1,
+		"condition": rule.Condition.Type,
+		"strict":    rule.Strict,
+	})
+}
+
+var (
+	setCheckboxChecked   string
+	setCheckboxUnchecked string
+)
+
+var setCheckboxCmd = func() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-checkbox <spreadsheet-id> <sheet-name> <range>",
+		Short: "Turn a range into checkboxes",
+		Args:  cobra.ExactArgs(3),
+		RunE:  runSetCheckbox,
+	}
+	cmd.Flags().StringVar(&setCheckboxChecked, "checked-value", "", "Custom value stored when checked (default TRUE)")
+	cmd.Flags().StringVar(&setCheckboxUnchecked, "unchecked-value", "", "Custom value stored when unchecked (default FALSE)")
+	return cmd
+}()
+
+func runSetCheckbox(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+	spreadsheetID := args[0]
+	sheetName := args[1]
+	rangeA1 := args[2]
+
+	condition := &sheets.BooleanCondition{Type: "BOOLEAN"}
+	switch {
+	case setCheckboxChecked != "" && setCheckboxUnchecked != "":
+		condition.Values = []*sheets.ConditionValue{
+			{UserEnteredValue: setCheckboxChecked},
+			{UserEnteredValue: setCheckboxUnchecked},
+		}
+	case setCheckboxChecked != "":
+		condition.Values = []*sheets.ConditionValue{{UserEnteredValue: setCheckboxChecked}}
+	case setCheckboxUnchecked != "":
+		return fmt.Errorf("--unchecked-value requires --checked-value")
+	}
+
+	rule := &sheets.DataValidationRule{
+		Condition: condition,
+		Strict:    true,
+	}
+
+	return applyDataValidation(ctx, spreadsheetID, sheetName, rangeA1, rule)
+}
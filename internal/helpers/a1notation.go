@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+
+	"google.golang.org/api/sheets/v4"
 )
 
 // A1ToGrid converts A1 notation (e.g., "B5") to 0-indexed grid coordinates
@@ -48,3 +50,105 @@ func ParseRange(rangeA1 string) (startCol, startRow, endCol, endRow int, err err
 
 	return startCol, startRow, endCol, endRow, nil
 }
+
+// ColumnToLetters converts a 0-indexed column number to its A1 letters (e.g., 27 -> "AB")
+func ColumnToLetters(col int) string {
+	letters := ""
+	for n := col + 1; n > 0; n = (n - 1) / 26 {
+		letters = string(rune('A'+(n-1)%26)) + letters
+	}
+	return letters
+}
+
+// GridToA1 converts 0-indexed grid coordinates to A1 notation (e.g., (1, 4) -> "B5")
+func GridToA1(col, row int) string {
+	return ColumnToLetters(col) + strconv.Itoa(row+1)
+}
+
+// StripSheetName removes the sheet prefix from a range returned by the API (e.g., "'My Sheet'!A1:B2" -> "A1:B2")
+func StripSheetName(rangeA1 string) string {
+	if i := strings.LastIndex(rangeA1, "!"); i >= 0 {
+		return rangeA1[i+1:]
+	}
+	return rangeA1
+}
+
+// ColumnIndex converts column letters (e.g., "C") to a 0-indexed column number
+func ColumnIndex(letters string) (int, error) {
+	letters = strings.ToUpper(letters)
+	if letters == "" {
+		return 0, fmt.Errorf("invalid column reference: %q", letters)
+	}
+
+	col := 0
+	for _, c := range letters {
+		if c < 'A' || c > 'Z' {
+			return 0, fmt.Errorf("invalid column reference: %s", letters)
+		}
+		col = col*26 + int(c-'A'+1)
+	}
+
+	return col - 1, nil
+}
+
+// ParseGridRange converts an A1 range into a GridRange on the given sheet.
+// Whole columns ("A:C") and whole rows ("2:5") leave the missing dimension unbounded.
+func ParseGridRange(sheetID int64, rangeA1 string) (*sheets.GridRange, error) {
+	startCol, startRow, endCol, endRow, err := ParseRange(rangeA1)
+	if err != nil {
+		return nil, err
+	}
+
+	gridRange := &sheets.GridRange{SheetId: sheetID}
+	if startRow >= 0 {
+		gridRange.StartRowIndex = int64(startRow)
+	}
+	if endRow >= 0 {
+		gridRange.EndRowIndex = int64(endRow + 1)
+	}
+	if startCol >= 0 {
+		gridRange.StartColumnIndex = int64(startCol)
+	}
+	if endCol >= 0 {
+		gridRange.EndColumnIndex = int64(endCol + 1)
+	}
+
+	return gridRange, nil
+}
+
+// GridRangeToA1 renders a GridRange as an A1 range without sheet prefix.
+// Unbounded rows or columns produce whole-column ("A:C") or whole-row ("2:5") ranges.
+func GridRangeToA1(gr *sheets.GridRange) string {
+	rowsBounded := gr.EndRowIndex > 0
+	colsBounded := gr.EndColumnIndex > 0
+
+	switch {
+	case rowsBounded && colsBounded:
+		start := GridToA1(int(gr.StartColumnIndex), int(gr.StartRowIndex))
+		end := GridToA1(int(gr.EndColumnIndex-1), int(gr.EndRowIndex-1))
+		if start == end {
+			return start
+		}
+		return start + ":" + end
+	case colsBounded:
+		return ColumnToLetters(int(gr.StartColumnIndex)) + ":" + ColumnToLetters(int(gr.EndColumnIndex-1))
+	case rowsBounded:
+		return fmt.Sprintf("%d:%d", gr.StartRowIndex+1, gr.EndRowIndex)
+	}
+	return ""
+}
+
+// SplitSheetRange splits "Sheet1!A1:B2" or "'My Sheet'!A1" into the sheet name and the A1 range.
+// A range without "!" returns an empty sheet name.
+func SplitSheetRange(value string) (sheetName, rangeA1 string) {
+	i := strings.LastIndex(value, "!")
+	if i < 0 {
+		return "", value
+	}
+
+	sheetName = value[:i]
+	if len(sheetName) >= 2 && sheetName[0] == '\'' && sheetName[len(sheetName)-1] == '\'' {
+		sheetName = strings.ReplaceAll(sheetName[1:len(sheetName)-1], "''", "'")
+	}
+	return sheetName, value[i+1:]
+}
@@ -0,0 +1,240 @@
+package helpers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+const (
+	CacheDirName  = "spreadsheet-manager"
+	CacheDirMode  = 0700
+	CacheFileMode = 0600
+	CacheTTLEnv   = "SPREADSHEET_MANAGER_CACHE_TTL"
+
+	// sheetCacheFields is the part of a spreadsheet kept in the metadata cache
+	sheetCacheFields    = "spreadsheetId,properties.title,sheets.properties"
+	sheetsAPIPathPrefix = "/v4/spreadsheets/"
+)
+
+// CacheTTL is bound to the --cache-ttl flag. Zero keeps the cache in memory only,
+// for the duration of one command
+var CacheTTL time.Duration
+
+// sheetCache guards the entries and the identity; the fetch of one spreadsheet holds only the
+// lock of its ID, so workers reading different spreadsheets do not wait for each other
+var sheetCache = struct {
+	sync.Mutex
+	entries  map[string]*sheets.Spreadsheet
+	locks    map[string]*sync.Mutex
+	identity string
+}{entries: map[string]*sheets.Spreadsheet{}, locks: map[string]*sync.Mutex{}}
+
+type sheetCacheEntry struct {
+	Fetched     time.Time           `json:"fetched"`
+	Spreadsheet *sheets.Spreadsheet `json:"spreadsheet"`
+}
+
+// GetSpreadsheetSheets returns the title and sheet properties (IDs, titles, indexes, grid sizes)
+// of a spreadsheet. Results are kept in memory, and on disk for --cache-ttl when it is set;
+// any write through the Sheets API drops the entry (see CacheInvalidatingTransport)
+func GetSpreadsheetSheets(service *sheets.Service, spreadsheetID string) (*sheets.Spreadsheet, error) {
+	lock := sheetCacheLock(spreadsheetID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	sheetCache.Lock()
+	spreadsheet, ok := sheetCache.entries[spreadsheetID]
+	path := sheetCacheFile(sheetCache.identity, spreadsheetID)
+	sheetCache.Unlock()
+	if ok {
+		return spreadsheet, nil
+	}
+
+	ttl := cacheTTL()
+	if spreadsheet = readSheetCacheFile(path, ttl); spreadsheet == nil {
+		var err error
+		spreadsheet, err = service.Spreadsheets.Get(spreadsheetID).Fields(sheetCacheFields).Do()
+		if err != nil {
+			return nil, err
+		}
+		if ttl > 0 {
+			writeSheetCacheFile(path, spreadsheet)
+		}
+	}
+
+	sheetCache.Lock()
+	sheetCache.entries[spreadsheetID] = spreadsheet
+	sheetCache.Unlock()
+	return spreadsheet, nil
+}
+
+// InvalidateSheetCache drops a spreadsheet from the memory and disk caches. It waits for a
+// fetch of the same spreadsheet in progress, which may have read it before the change
+func InvalidateSheetCache(spreadsheetID string) {
+	lock := sheetCacheLock(spreadsheetID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	sheetCache.Lock()
+	defer sheetCache.Unlock()
+	delete(sheetCache.entries, spreadsheetID)
+	if path := sheetCacheFile(sheetCache.identity, spreadsheetID); path != "" {
+		_ = os.Remove(path)
+	}
+}
+
+// ClearSpreadsheetCache drops a spreadsheet from the memory cache and from the disk cache of
+// every identity
+func ClearSpreadsheetCache(spreadsheetID string) error {
+	InvalidateSheetCache(spreadsheetID)
+
+	dir := SheetCacheDir()
+	if dir == "" || strings.ContainsAny(spreadsheetID, `/\.*?[`) {
+		return nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*", spreadsheetID+".json"))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetCacheIdentity names who the API calls are made as (profile, token or service account).
+// Cache files are kept apart per identity, so one never reads metadata fetched with the access
+// of another; without an identity only the memory cache is used
+func SetCacheIdentity(identity string) {
+	sheetCache.Lock()
+	defer sheetCache.Unlock()
+
+	if identity != sheetCache.identity {
+		sheetCache.entries = map[string]*sheets.Spreadsheet{}
+		sheetCache.identity = identity
+	}
+}
+
+func sheetCacheLock(spreadsheetID string) *sync.Mutex {
+	sheetCache.Lock()
+	defer sheetCache.Unlock()
+
+	lock, ok := sheetCache.locks[spreadsheetID]
+	if !ok {
+		lock = &sync.Mutex{}
+		sheetCache.locks[spreadsheetID] = lock
+	}
+	return lock
+}
+
+// ClearSheetCache drops every cached spreadsheet
+func ClearSheetCache() error {
+	sheetCache.Lock()
+	defer sheetCache.Unlock()
+
+	sheetCache.entries = map[string]*sheets.Spreadsheet{}
+	dir := SheetCacheDir()
+	if dir == "" {
+		return nil
+	}
+	return os.RemoveAll(dir)
+}
+
+// SheetCacheDir is where cached spreadsheet metadata is written, an empty string when the
+// system has no cache directory
+func SheetCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, CacheDirName, "sheets")
+}
+
+func cacheTTL() time.Duration {
+	if CacheTTL != 0 {
+		return CacheTTL
+	}
+	ttl, err := time.ParseDuration(os.Getenv(CacheTTLEnv))
+	if err != nil {
+		return 0
+	}
+	return ttl
+}
+
+// sheetCacheFile is SheetCacheDir/<identity hash>/<id>.json
+func sheetCacheFile(identity, spreadsheetID string) string {
+	dir := SheetCacheDir()
+	if dir == "" || identity == "" || spreadsheetID == "" || strings.ContainsAny(spreadsheetID, `/\.`) {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(identity))
+	return filepath.Join(dir, hex.EncodeToString(sum[:16]), spreadsheetID+".json")
+}
+
+func readSheetCacheFile(path string, ttl time.Duration) *sheets.Spreadsheet {
+	if ttl <= 0 || path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var entry sheetCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Spreadsheet == nil || time.Since(entry.Fetched) > ttl {
+		return nil
+	}
+	return entry.Spreadsheet
+}
+
+// writeSheetCacheFile is best effort: a cache that cannot be written only costs an API call
+func writeSheetCacheFile(path string, spreadsheet *sheets.Spreadsheet) {
+	if path == "" {
+		return
+	}
+
+	data, err := json.Marshal(sheetCacheEntry{Fetched: time.Now(), Spreadsheet: spreadsheet})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), CacheDirMode); err != nil {
+		return
+	}
+	_ = os.WriteFile(path, data, CacheFileMode)
+}
+
+// CacheInvalidatingTransport drops the cached metadata of a spreadsheet whenever a request
+// other than a read is sent for it, so commands never see sheets or grid sizes they changed
+func CacheInvalidatingTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return cacheInvalidatingTransport{base: base}
+}
+
+type cacheInvalidatingTransport struct {
+	base http.RoundTripper
+}
+
+func (t cacheInvalidatingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if req.Method != http.MethodGet {
+		if rest, ok := strings.CutPrefix(req.URL.Path, sheetsAPIPathPrefix); ok {
+			spreadsheetID, _, _ := strings.Cut(rest, "/")
+			spreadsheetID, _, _ = strings.Cut(spreadsheetID, ":")
+			InvalidateSheetCache(spreadsheetID)
+		}
+	}
+	return resp, err
+}
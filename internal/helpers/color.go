@@ -1,6 +1,8 @@
 package helpers
 
 import (
+	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
@@ -34,3 +36,16 @@ func ParseColor(hexColor string) *sheets.Color {
 		Blue:  float64(b) / RGBMaxValue,
 	}
 }
+
+// ColorToHex converts a Google Sheets Color object back to a hex string (e.g., "#ff0000")
+// It returns an empty string for a nil color
+func ColorToHex(color *sheets.Color) string {
+	if color == nil {
+		return ""
+	}
+	return fmt.Sprintf("#%02x%02x%02x",
+		int(math.Round(color.Red*RGBMaxValue)),
+		int(math.Round(color.Green*RGBMaxValue)),
+		int(math.Round(color.Blue*RGBMaxValue)),
+	)
+}
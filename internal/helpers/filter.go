@@ -0,0 +1,176 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FilterOpContains    = "contains"
+	FilterOpEqual       = "=="
+	FilterOpGreater     = ">"
+	FilterOpGreaterOrEq = ">="
+	FilterOpLess        = "<"
+	FilterOpLessOrEq    = "<="
+	FilterOpNotContains = "!contains"
+	FilterOpNotEqual    = "!="
+)
+
+// Operators ordered so that longer symbols win over their prefixes at the same position
+var filterOperators = []string{
+	FilterOpNotContains,
+	FilterOpContains,
+	FilterOpEqual,
+	FilterOpNotEqual,
+	FilterOpGreaterOrEq,
+	FilterOpLessOrEq,
+	FilterOpGreater,
+	FilterOpLess,
+}
+
+var filterDateLayouts = []string{
+	"2006-01-02",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+	"1/2/2006",
+	"1/2/2006 15:04:05",
+}
+
+// Filter is a single "<column> <operator> <value>" row condition
+type Filter struct {
+	Column   string
+	Operator string
+	Value    string
+}
+
+// ParseFilter parses an expression such as `status == "done"` or `C < 2024-01-01`
+func ParseFilter(expr string) (*Filter, error) {
+	bestIndex, bestOp := -1, ""
+	for _, op := range filterOperators {
+		index := strings.Index(expr, op)
+		if op == FilterOpContains || op == FilterOpNotContains {
+			index = strings.Index(expr, " "+op+" ")
+			if index >= 0 {
+				index++
+			}
+		}
+		if index >= 0 && (bestIndex < 0 || index < bestIndex) {
+			bestIndex, bestOp = index, op
+		}
+	}
+
+	if bestIndex < 0 {
+		return nil, fmt.Errorf("invalid filter expression %q: expected <column> <operator> <value>", expr)
+	}
+
+	column := unquote(strings.TrimSpace(expr[:bestIndex]))
+	if column == "" {
+		return nil, fmt.Errorf("invalid filter expression %q: missing column", expr)
+	}
+
+	return &Filter{
+		Column:   column,
+		Operator: bestOp,
+		Value:    unquote(strings.TrimSpace(expr[bestIndex+len(bestOp):])),
+	}, nil
+}
+
+// Match reports whether a cell value satisfies the filter.
+// Values are compared as numbers, then as dates, then as strings.
+// Ordered operators never match when only one side is a number or a date,
+// and never match an empty cell unless the filter value is empty too.
+func (f *Filter) Match(cell interface{}) bool {
+	text := ""
+	if cell != nil {
+		text = CellString(cell)
+	}
+
+	switch f.Operator {
+	case FilterOpContains:
+		return strings.Contains(text, f.Value)
+	case FilterOpNotContains:
+		return !strings.Contains(text, f.Value)
+	}
+
+	cmp, comparable := compareValues(cell, text, f.Value)
+	switch f.Operator {
+	case FilterOpEqual:
+		return comparable && cmp == 0
+	case FilterOpNotEqual:
+		return !comparable || cmp != 0
+	}
+
+	if !comparable || (text == "" && f.Value != "") {
+		return false
+	}
+
+	switch f.Operator {
+	case FilterOpGreater:
+		return cmp > 0
+	case FilterOpGreaterOrEq:
+		return cmp >= 0
+	case FilterOpLess:
+		return cmp < 0
+	case FilterOpLessOrEq:
+		return cmp <= 0
+	}
+
+	return false
+}
+
+// compareValues compares a cell with a filter value and reports whether both
+// sides share a type; a number or date on only one side is not comparable
+func compareValues(cell interface{}, text, value string) (int, bool) {
+	x, cellIsNumber := cell.(float64)
+	if !cellIsNumber {
+		var err error
+		x, err = strconv.ParseFloat(text, 64)
+		cellIsNumber = err == nil
+	}
+	y, err := strconv.ParseFloat(value, 64)
+	valueIsNumber := err == nil
+	if cellIsNumber || valueIsNumber {
+		if cellIsNumber != valueIsNumber {
+			return 0, false
+		}
+		switch {
+		case x < y:
+			return -1, true
+		case x > y:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	cellDate, cellIsDate := parseDate(text)
+	valueDate, valueIsDate := parseDate(value)
+	if cellIsDate || valueIsDate {
+		if cellIsDate != valueIsDate {
+			return 0, false
+		}
+		return cellDate.Compare(valueDate), true
+	}
+
+	return strings.Compare(text, value), true
+}
+
+func parseDate(value string) (time.Time, bool) {
+	for _, layout := range filterDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if (first == '"' || first == '\'') && first == last {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
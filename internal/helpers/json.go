@@ -3,14 +3,21 @@ package helpers
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 )
 
 // PrintJSON marshals a value to indented JSON and prints it to stdout
 func PrintJSON(v interface{}) error {
+	return FprintJSON(os.Stdout, v)
+}
+
+// FprintJSON marshals a value to indented JSON and prints it to w
+func FprintJSON(w io.Writer, v interface{}) error {
 	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return err
 	}
-	fmt.Println(string(data))
+	fmt.Fprintln(w, string(data))
 	return nil
 }
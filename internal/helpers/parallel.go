@@ -0,0 +1,24 @@
+package helpers
+
+import "sync"
+
+// RunParallel calls fn for every index below count, at most concurrency at a time, and returns
+// once all calls are done. Calls run in any order, so fn should only write results at its index
+func RunParallel(concurrency, count int, fn func(i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(max(concurrency, 1), count); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < count; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,126 @@
+package helpers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ProgressBarWidth = 20
+	ProgressInterval = 500 * time.Millisecond
+)
+
+// Quiet is bound to the --quiet flag and turns progress reporting off
+var Quiet bool
+
+// progressOutput serializes progress lines, which may come from several workers
+var progressOutput = struct {
+	sync.Mutex
+	active int
+	inLine *Progress
+}{}
+
+// Progress reports the advance of a long operation on stderr. Nothing is shown during the
+// first ProgressInterval, so quick operations stay silent. On a terminal a single operation
+// redraws one line with a bar; otherwise, or when several run in parallel, it prints one line
+// per update, at most every ProgressInterval
+type Progress struct {
+	label    string
+	start    time.Time
+	last     time.Time
+	terminal bool
+	done     bool
+}
+
+// NewProgress starts reporting an operation, or returns nil with --quiet. All methods accept
+// a nil Progress
+func NewProgress(label string) *Progress {
+	if Quiet {
+		return nil
+	}
+
+	progressOutput.Lock()
+	defer progressOutput.Unlock()
+	progressOutput.active++
+
+	info, err := os.Stderr.Stat()
+	return &Progress{
+		label:    label,
+		start:    time.Now(),
+		terminal: err == nil && info.Mode()&os.ModeCharDevice != 0,
+	}
+}
+
+// Update reports status, e.g. "12000 rows, 3 chunks". fraction is how much of the work is
+// done, from 0 to 1, or negative when the total is unknown (no bar, percentage or ETA)
+func (p *Progress) Update(fraction float64, status string) {
+	if p == nil || p.done || time.Since(p.start) < ProgressInterval || time.Since(p.last) < ProgressInterval {
+		return
+	}
+	p.print(fraction, status)
+}
+
+// Finish prints the final status, if any progress was shown, and stops the progress
+func (p *Progress) Finish(status string) {
+	if p == nil || p.done {
+		return
+	}
+	if !p.last.IsZero() {
+		p.print(1, status)
+	}
+	p.Stop()
+}
+
+// Stop ends the progress line without a final status. It is meant to be deferred, so a
+// failed operation does not leave an open line before its error
+func (p *Progress) Stop() {
+	if p == nil || p.done {
+		return
+	}
+	p.done = true
+
+	progressOutput.Lock()
+	defer progressOutput.Unlock()
+	progressOutput.active--
+	if progressOutput.inLine == p {
+		fmt.Fprintln(os.Stderr)
+		progressOutput.inLine = nil
+	}
+}
+
+func (p *Progress) print(fraction float64, status string) {
+	p.last = time.Now()
+	line := p.label + ": " + status
+	if fraction >= 0 {
+		fraction = min(fraction, 1)
+		line += fmt.Sprintf(" (%.0f%%", fraction*100)
+		if elapsed := time.Since(p.start); fraction > 0 && fraction < 1 {
+			eta := time.Duration(float64(elapsed) * (1 - fraction) / fraction)
+			line += ", ETA " + eta.Round(time.Second).String()
+		}
+		line += ")"
+	}
+
+	progressOutput.Lock()
+	defer progressOutput.Unlock()
+
+	if progressOutput.inLine != nil && progressOutput.inLine != p {
+		fmt.Fprintln(os.Stderr)
+		progressOutput.inLine = nil
+	}
+	if !p.terminal || progressOutput.active > 1 {
+		fmt.Fprintln(os.Stderr, line)
+		return
+	}
+
+	if fraction >= 0 {
+		filled := int(fraction * ProgressBarWidth)
+		line = "[" + strings.Repeat("=", filled) + strings.Repeat(" ", ProgressBarWidth-filled) + "] " + line
+	}
+	// Carriage return and erase, so the line is redrawn in place
+	fmt.Fprint(os.Stderr, "\r\033[K"+line)
+	progressOutput.inLine = p
+}
@@ -2,22 +2,119 @@ package helpers
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"google.golang.org/api/sheets/v4"
 )
 
-// GetSheetID retrieves the numeric sheet ID for a given sheet name
-func GetSheetID(service *sheets.Service, spreadsheetID, sheetName string) (int64, error) {
-	spreadsheet, err := service.Spreadsheets.Get(spreadsheetID).Do()
+const (
+	SheetRefGIDPrefix   = "gid:"
+	SheetRefIndexPrefix = "index:"
+)
+
+// GetSheetID retrieves the numeric sheet ID for a given sheet reference
+func GetSheetID(service *sheets.Service, spreadsheetID, sheetRef string) (int64, error) {
+	props, err := GetSheetProperties(service, spreadsheetID, sheetRef)
+	if err != nil {
+		return 0, err
+	}
+	return props.SheetId, nil
+}
+
+// GetSheetProperties retrieves the properties (ID, index, grid size...) of a sheet by reference
+func GetSheetProperties(service *sheets.Service, spreadsheetID, sheetRef string) (*sheets.SheetProperties, error) {
+	spreadsheet, err := GetSpreadsheetSheets(service, spreadsheetID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve spreadsheet: %w", err)
+	}
+
+	return FindSheet(spreadsheet, sheetRef)
+}
+
+// ResolveSheetTitle returns the title of a sheet reference for use in A1 ranges.
+// Plain names are returned as-is without an API call.
+func ResolveSheetTitle(service *sheets.Service, spreadsheetID, sheetRef string) (string, error) {
+	if !isPrefixedSheetRef(sheetRef) {
+		return sheetRef, nil
+	}
+
+	props, err := GetSheetProperties(service, spreadsheetID, sheetRef)
 	if err != nil {
-		return 0, fmt.Errorf("unable to retrieve spreadsheet: %w", err)
+		return "", err
 	}
+	return props.Title, nil
+}
 
+// FindSheet looks up a sheet in an already retrieved spreadsheet.
+// The reference is a sheet title, "gid:<sheet-id>", or "index:<0-based tab position>".
+func FindSheet(spreadsheet *sheets.Spreadsheet, sheetRef string) (*sheets.SheetProperties, error) {
+	var byTitle *sheets.SheetProperties
 	for _, sheet := range spreadsheet.Sheets {
-		if sheet.Properties.Title == sheetName {
-			return sheet.Properties.SheetId, nil
+		if sheet.Properties.Title == sheetRef {
+			byTitle = sheet.Properties
+			break
 		}
 	}
 
-	return 0, fmt.Errorf("sheet '%s' not found", sheetName)
+	if !isPrefixedSheetRef(sheetRef) {
+		if byTitle == nil {
+			return nil, fmt.Errorf("sheet '%s' not found", sheetRef)
+		}
+		return byTitle, nil
+	}
+
+	byRef, err := findSheetByPrefixedRef(spreadsheet, sheetRef)
+	switch {
+	case err != nil && byTitle != nil:
+		return byTitle, nil
+	case err != nil:
+		return nil, err
+	case byTitle != nil && byTitle.SheetId != byRef.SheetId:
+		return nil, fmt.Errorf("sheet reference '%s' is ambiguous: it matches the title of sheet %d and resolves to sheet '%s' (%d)",
+			sheetRef, byTitle.SheetId, byRef.Title, byRef.SheetId)
+	}
+
+	return byRef, nil
+}
+
+// SheetRange builds an A1 range for a sheet title, quoting the title so spaces and symbols are safe
+// An empty rangeA1 yields a range covering the whole sheet
+func SheetRange(sheetTitle, rangeA1 string) string {
+	quoted := "'" + strings.ReplaceAll(sheetTitle, "'", "''") + "'"
+	if rangeA1 == "" {
+		return quoted
+	}
+	return quoted + "!" + rangeA1
+}
+
+func isPrefixedSheetRef(sheetRef string) bool {
+	return strings.HasPrefix(sheetRef, SheetRefGIDPrefix) || strings.HasPrefix(sheetRef, SheetRefIndexPrefix)
+}
+
+func findSheetByPrefixedRef(spreadsheet *sheets.Spreadsheet, sheetRef string) (*sheets.SheetProperties, error) {
+	if value, ok := strings.CutPrefix(sheetRef, SheetRefGIDPrefix); ok {
+		gid, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sheet reference '%s': gid must be a number", sheetRef)
+		}
+		for _, sheet := range spreadsheet.Sheets {
+			if sheet.Properties.SheetId == gid {
+				return sheet.Properties, nil
+			}
+		}
+		return nil, fmt.Errorf("no sheet with gid %d", gid)
+	}
+
+	value := strings.TrimPrefix(sheetRef, SheetRefIndexPrefix)
+	index, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sheet reference '%s': index must be a number", sheetRef)
+	}
+	for _, sheet := range spreadsheet.Sheets {
+		if sheet.Properties.Index == index {
+			return sheet.Properties, nil
+		}
+	}
+	return nil, fmt.Errorf("no sheet at index %d (spreadsheet has %d sheets)", index, len(spreadsheet.Sheets))
 }
@@ -0,0 +1,73 @@
+package helpers
+
+import (
+	"fmt"
+
+	"google.golang.org/api/sheets/v4"
+)
+
+// DefaultPageSize is the number of rows fetched per request by StreamRows
+const DefaultPageSize = 1000
+
+// StreamRows reads a sheet in windows of pageSize rows and passes each batch of rows to fn.
+// Rows come out exactly as a single Values.Get of the whole sheet would return them:
+// empty rows trimmed at the end of a window are re-emitted when later data follows.
+// The row count is read fresh, as the cached one may miss rows added since.
+func StreamRows(service *sheets.Service, spreadsheetID string, sheet *sheets.SheetProperties, pageSize int, valueRender, dateRender string, fn func(rows [][]interface{}) error) error {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	rowCount, err := freshRowCount(service, spreadsheetID, sheet.SheetId)
+	if err != nil {
+		return err
+	}
+
+	pendingEmpty := 0
+	for start := 1; start <= rowCount; start += pageSize {
+		end := min(start+pageSize-1, rowCount)
+		window := fmt.Sprintf("%d:%d", start, end)
+
+		resp, err := service.Spreadsheets.Values.Get(spreadsheetID, SheetRange(sheet.Title, window)).
+			ValueRenderOption(valueRender).
+			DateTimeRenderOption(dateRender).Do()
+		if err != nil {
+			return fmt.Errorf("unable to read rows %s: %w", window, err)
+		}
+
+		if len(resp.Values) == 0 {
+			pendingEmpty += end - start + 1
+			continue
+		}
+
+		rows := resp.Values
+		if pendingEmpty > 0 {
+			rows = append(make([][]interface{}, pendingEmpty), rows...)
+		}
+		if err := fn(rows); err != nil {
+			return err
+		}
+		pendingEmpty = end - start + 1 - len(resp.Values)
+	}
+
+	return nil
+}
+
+// freshRowCount reads the grid row count of a sheet, bypassing the metadata cache
+func freshRowCount(service *sheets.Service, spreadsheetID string, sheetID int64) (int, error) {
+	spreadsheet, err := service.Spreadsheets.Get(spreadsheetID).
+		Fields("sheets.properties(sheetId,gridProperties.rowCount)").Do()
+	if err != nil {
+		return 0, fmt.Errorf("unable to read the sheet size: %w", err)
+	}
+
+	for _, sheet := range spreadsheet.Sheets {
+		if sheet.Properties.SheetId == sheetID {
+			if sheet.Properties.GridProperties == nil {
+				return 0, nil
+			}
+			return int(sheet.Properties.GridProperties.RowCount), nil
+		}
+	}
+	return 0, fmt.Errorf("sheet %d not found", sheetID)
+}
@@ -0,0 +1,14 @@
+package helpers
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// CellString renders a cell value as text, keeping unformatted numbers out of exponent notation
+func CellString(cell interface{}) string {
+	if number, ok := cell.(float64); ok {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	return fmt.Sprintf("%v", cell)
+}